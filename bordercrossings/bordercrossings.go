@@ -0,0 +1,167 @@
+package bordercrossings
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"alpineworks.io/wsdot"
+)
+
+const (
+	getBorderCrossingsAsJsonURL = "http://www.wsdot.wa.gov/Traffic/api/BorderCrossings/BorderCrossingsREST.svc/GetBorderCrossingsAsJson"
+
+	// WaitTimeUnavailable is reported by WSDOT when a lane has no current
+	// wait time, typically because it is closed.
+	WaitTimeUnavailable = -1
+)
+
+type BorderCrossingsClient struct {
+	wsdot *wsdot.WSDOTClient
+}
+
+func NewBorderCrossingsClient(wsdotClient *wsdot.WSDOTClient) (*BorderCrossingsClient, error) {
+	if wsdotClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return &BorderCrossingsClient{
+		wsdot: wsdotClient,
+	}, nil
+}
+
+type CrossingName string
+
+const (
+	CrossingI5          CrossingName = "I5"
+	CrossingI5Nexus     CrossingName = "I5Nexus"
+	CrossingI5Trucks    CrossingName = "I5Trucks"
+	CrossingSR543       CrossingName = "SR543"
+	CrossingSR543Nexus  CrossingName = "SR543Nexus"
+	CrossingSR543Trucks CrossingName = "SR543Trucks"
+	CrossingSR539       CrossingName = "SR539"
+	CrossingSR539Nexus  CrossingName = "SR539Nexus"
+	CrossingSR539Trucks CrossingName = "SR539Trucks"
+	CrossingSR9         CrossingName = "SR9"
+	CrossingSR9Nexus    CrossingName = "SR9Nexus"
+	CrossingSR9Trucks   CrossingName = "SR9Trucks"
+)
+
+type LaneType string
+
+const (
+	LaneTypeGeneral LaneType = "general"
+	LaneTypeNexus   LaneType = "nexus"
+	LaneTypeTrucks  LaneType = "trucks"
+)
+
+// LaneType returns the lane the wait time applies to, derived from the
+// suffix WSDOT appends to the crossing name.
+func (c CrossingName) LaneType() LaneType {
+	switch {
+	case strings.HasSuffix(string(c), "Nexus"):
+		return LaneTypeNexus
+	case strings.HasSuffix(string(c), "Trucks"):
+		return LaneTypeTrucks
+	default:
+		return LaneTypeGeneral
+	}
+}
+
+// Route returns the crossing name without its lane suffix, e.g. "SR543" for
+// "SR543Trucks".
+func (c CrossingName) Route() string {
+	route := strings.TrimSuffix(string(c), "Nexus")
+	return strings.TrimSuffix(route, "Trucks")
+}
+
+// PortOfEntry returns the common name of the port of entry for the crossing.
+func (c CrossingName) PortOfEntry() string {
+	switch c.Route() {
+	case "I5":
+		return "Peace Arch"
+	case "SR543":
+		return "Pacific Highway"
+	case "SR539":
+		return "Lynden"
+	case "SR9":
+		return "Sumas"
+	default:
+		return c.Route()
+	}
+}
+
+type BorderCrossingLocation struct {
+	Description *string `json:"Description"`
+	Direction   *string `json:"Direction"`
+	Latitude    float64 `json:"Latitude"`
+	Longitude   float64 `json:"Longitude"`
+	MilePost    float64 `json:"MilePost"`
+	RoadName    string  `json:"RoadName"`
+}
+
+type BorderCrossing struct {
+	BorderCrossingLocation *BorderCrossingLocation `json:"BorderCrossingLocation"`
+	CrossingName           CrossingName            `json:"CrossingName"`
+	Time                   string                  `json:"Time"`
+	WaitTime               int                     `json:"WaitTime"`
+}
+
+func (b BorderCrossing) Available() bool {
+	return b.WaitTime != WaitTimeUnavailable
+}
+
+func (c *BorderCrossingsClient) GetBorderCrossings() ([]BorderCrossing, error) {
+	req, err := http.NewRequest(http.MethodGet, getBorderCrossingsAsJsonURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	q := req.URL.Query()
+	q.Add(wsdot.ParamCamerasAccessCodeKey, c.wsdot.ApiKey)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.wsdot.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var crossings []BorderCrossing
+	if err := json.NewDecoder(resp.Body).Decode(&crossings); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return crossings, nil
+}
+
+// RankByWait returns the crossings with a current wait time, shortest wait
+// first. When laneTypes are given only crossings for those lanes are ranked.
+func RankByWait(crossings []BorderCrossing, laneTypes ...LaneType) []BorderCrossing {
+	var ranked []BorderCrossing
+	for _, crossing := range crossings {
+		if !crossing.Available() {
+			continue
+		}
+
+		if len(laneTypes) > 0 && !slices.Contains(laneTypes, crossing.CrossingName.LaneType()) {
+			continue
+		}
+
+		ranked = append(ranked, crossing)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].WaitTime < ranked[j].WaitTime
+	})
+
+	return ranked
+}
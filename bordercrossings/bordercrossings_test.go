@@ -0,0 +1,96 @@
+package bordercrossings
+
+import (
+	"slices"
+	"testing"
+)
+
+func crossingNames(crossings []BorderCrossing) []CrossingName {
+	var names []CrossingName
+	for _, crossing := range crossings {
+		names = append(names, crossing.CrossingName)
+	}
+
+	return names
+}
+
+func TestRankByWait(t *testing.T) {
+	crossings := []BorderCrossing{
+		{CrossingName: CrossingI5, WaitTime: 25},
+		{CrossingName: CrossingI5Nexus, WaitTime: 5},
+		{CrossingName: CrossingSR543, WaitTime: 10},
+		{CrossingName: CrossingSR543Trucks, WaitTime: WaitTimeUnavailable},
+		{CrossingName: CrossingSR539, WaitTime: 10},
+		{CrossingName: CrossingSR9Trucks, WaitTime: 40},
+		{CrossingName: CrossingSR9, WaitTime: 0},
+		// a name without a known lane suffix is a general lane
+		{CrossingName: "SR15", WaitTime: 15},
+	}
+
+	tests := []struct {
+		name      string
+		laneTypes []LaneType
+		want      []CrossingName
+	}{
+		{
+			name: "all lanes",
+			want: []CrossingName{CrossingSR9, CrossingI5Nexus, CrossingSR543, CrossingSR539, "SR15", CrossingI5, CrossingSR9Trucks},
+		},
+		{
+			name:      "general lanes keep ties in input order",
+			laneTypes: []LaneType{LaneTypeGeneral},
+			want:      []CrossingName{CrossingSR9, CrossingSR543, CrossingSR539, "SR15", CrossingI5},
+		},
+		{
+			name:      "several lane types",
+			laneTypes: []LaneType{LaneTypeNexus, LaneTypeTrucks},
+			want:      []CrossingName{CrossingI5Nexus, CrossingSR9Trucks},
+		},
+		{
+			name:      "unavailable lanes are dropped",
+			laneTypes: []LaneType{LaneTypeTrucks},
+			want:      []CrossingName{CrossingSR9Trucks},
+		},
+		{
+			name:      "unknown lane type",
+			laneTypes: []LaneType{"bus"},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := crossingNames(RankByWait(crossings, tt.laneTypes...)); !slices.Equal(got, tt.want) {
+				t.Errorf("RankByWait() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrossingName(t *testing.T) {
+	tests := []struct {
+		name        CrossingName
+		laneType    LaneType
+		route       string
+		portOfEntry string
+	}{
+		{name: CrossingI5, laneType: LaneTypeGeneral, route: "I5", portOfEntry: "Peace Arch"},
+		{name: CrossingSR543Trucks, laneType: LaneTypeTrucks, route: "SR543", portOfEntry: "Pacific Highway"},
+		{name: CrossingSR539Nexus, laneType: LaneTypeNexus, route: "SR539", portOfEntry: "Lynden"},
+		{name: "SR15", laneType: LaneTypeGeneral, route: "SR15", portOfEntry: "SR15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.name), func(t *testing.T) {
+			if got := tt.name.LaneType(); got != tt.laneType {
+				t.Errorf("LaneType() = %v, want %v", got, tt.laneType)
+			}
+			if got := tt.name.Route(); got != tt.route {
+				t.Errorf("Route() = %v, want %v", got, tt.route)
+			}
+			if got := tt.name.PortOfEntry(); got != tt.portOfEntry {
+				t.Errorf("PortOfEntry() = %v, want %v", got, tt.portOfEntry)
+			}
+		})
+	}
+}
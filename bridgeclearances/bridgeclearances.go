@@ -0,0 +1,105 @@
+package bridgeclearances
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"alpineworks.io/wsdot"
+)
+
+const (
+	getClearancesAsJsonURL = "http://www.wsdot.wa.gov/Traffic/api/Bridges/ClearanceREST.svc/GetClearancesAsJson"
+
+	ParamRoute = "Route"
+)
+
+type BridgeClearancesClient struct {
+	wsdot *wsdot.WSDOTClient
+}
+
+func NewBridgeClearancesClient(wsdotClient *wsdot.WSDOTClient) (*BridgeClearancesClient, error) {
+	if wsdotClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return &BridgeClearancesClient{
+		wsdot: wsdotClient,
+	}, nil
+}
+
+type BridgeClearance struct {
+	APILastUpdate                    string  `json:"APILastUpdate"`
+	BridgeNumber                     string  `json:"BridgeNumber"`
+	ControlEntityGuid                string  `json:"ControlEntityGuid"`
+	CrossingDescription              string  `json:"CrossingDescription"`
+	CrossingLocationId               int     `json:"CrossingLocationId"`
+	CrossingRecordGuid               string  `json:"CrossingRecordGuid"`
+	InventoryDirection               string  `json:"InventoryDirection"`
+	Latitude                         float64 `json:"Latitude"`
+	LocationGuid                     string  `json:"LocationGuid"`
+	Longitude                        float64 `json:"Longitude"`
+	RouteDate                        string  `json:"RouteDate"`
+	SRMP                             float64 `json:"SRMP"`
+	SRMPAheadBackIndicator           string  `json:"SRMPAheadBackIndicator"`
+	StateRouteID                     string  `json:"StateRouteID"`
+	StateStructureId                 string  `json:"StateStructureId"`
+	VerticalClearanceMaximumFeetInch string  `json:"VerticalClearanceMaximumFeetInch"`
+	VerticalClearanceMaximumInches   int     `json:"VerticalClearanceMaximumInches"`
+	VerticalClearanceMinimumFeetInch string  `json:"VerticalClearanceMinimumFeetInch"`
+	VerticalClearanceMinimumInches   int     `json:"VerticalClearanceMinimumInches"`
+}
+
+func (c *BridgeClearancesClient) GetClearances() ([]BridgeClearance, error) {
+	return c.getClearances("")
+}
+
+func (c *BridgeClearancesClient) GetClearancesByRoute(stateRouteID string) ([]BridgeClearance, error) {
+	return c.getClearances(stateRouteID)
+}
+
+func (c *BridgeClearancesClient) getClearances(stateRouteID string) ([]BridgeClearance, error) {
+	req, err := http.NewRequest(http.MethodGet, getClearancesAsJsonURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	q := req.URL.Query()
+	q.Add(wsdot.ParamCamerasAccessCodeKey, c.wsdot.ApiKey)
+	if stateRouteID != "" {
+		q.Add(ParamRoute, stateRouteID)
+	}
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.wsdot.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var clearances []BridgeClearance
+	if err := json.NewDecoder(resp.Body).Decode(&clearances); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return clearances, nil
+}
+
+// ClearancesBelowHeight returns the structures whose minimum vertical
+// clearance is lower than heightInInches. Structures without a reported
+// minimum clearance are skipped.
+func ClearancesBelowHeight(clearances []BridgeClearance, heightInInches int) []BridgeClearance {
+	var low []BridgeClearance
+	for _, clearance := range clearances {
+		if clearance.VerticalClearanceMinimumInches > 0 && clearance.VerticalClearanceMinimumInches < heightInInches {
+			low = append(low, clearance)
+		}
+	}
+
+	return low
+}
@@ -0,0 +1,41 @@
+package bridgeclearances
+
+import (
+	"testing"
+)
+
+func TestClearancesBelowHeight(t *testing.T) {
+	clearances := []BridgeClearance{
+		{BridgeNumber: "5/518", VerticalClearanceMinimumFeetInch: "13 ft 6 in", VerticalClearanceMinimumInches: 13*12 + 6},
+		{BridgeNumber: "5/520", VerticalClearanceMinimumFeetInch: "14 ft 0 in", VerticalClearanceMinimumInches: 14 * 12},
+		{BridgeNumber: "5/522", VerticalClearanceMinimumFeetInch: "16 ft 1 in", VerticalClearanceMinimumInches: 16*12 + 1},
+		{BridgeNumber: "5/524"},
+	}
+
+	tests := []struct {
+		name           string
+		heightInInches int
+		want           []string
+	}{
+		{name: "Equal to the lowest clearance", heightInInches: 13*12 + 6, want: nil},
+		{name: "One inch over the lowest clearance", heightInInches: 13*12 + 7, want: []string{"5/518"}},
+		{name: "Feet and inches converted to inches", heightInInches: 14*12 + 1, want: []string{"5/518", "5/520"}},
+		{name: "Equal to a whole number of feet", heightInInches: 14 * 12, want: []string{"5/518"}},
+		{name: "Taller than every clearance", heightInInches: 17 * 12, want: []string{"5/518", "5/520", "5/522"}},
+		{name: "Unknown height", heightInInches: 0, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClearancesBelowHeight(clearances, tt.heightInInches)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ClearancesBelowHeight() = %d clearances, want %v", len(got), tt.want)
+			}
+			for i := range got {
+				if got[i].BridgeNumber != tt.want[i] {
+					t.Errorf("ClearancesBelowHeight()[%d] = %s, want %s", i, got[i].BridgeNumber, tt.want[i])
+				}
+			}
+		})
+	}
+}
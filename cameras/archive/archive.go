@@ -0,0 +1,410 @@
+// Package archive keeps a rolling on-disk archive of camera frames.
+//
+// Frames are stored as <dir>/<camera id>/<yyyy-mm-dd>/<hhmmss>.<ext> in UTC,
+// using the frame's Last-Modified time when the server provides one.
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/cameras"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "150405"
+
+	DefaultInterval = time.Minute
+)
+
+var (
+	ErrNoDirectory = errors.New("no archive directory")
+)
+
+type Frame struct {
+	CameraID int
+	Time     time.Time
+	Path     string
+	Size     int64
+}
+
+type Archiver struct {
+	dir          string
+	cameraIDs    []int
+	interval     time.Duration
+	maxAge       time.Duration
+	maxBytes     int64
+	errorHandler func(error)
+
+	getCamera  func(cameraID int) (*cameras.Camera, error)
+	fetchImage func(ctx context.Context, camera cameras.Camera) (*cameras.Image, error)
+	now        func() time.Time
+
+	mu      sync.Mutex
+	cameras map[int]cameras.Camera
+	hashes  map[int][sha256.Size]byte
+}
+
+type ArchiverOption func(*Archiver)
+
+func NewArchiver(camerasClient *cameras.CamerasClient, dir string, cameraIDs []int, options ...ArchiverOption) (*Archiver, error) {
+	if camerasClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	if dir == "" {
+		return nil, ErrNoDirectory
+	}
+
+	archiver := &Archiver{
+		dir:        dir,
+		cameraIDs:  cameraIDs,
+		interval:   DefaultInterval,
+		getCamera:  camerasClient.GetCamera,
+		fetchImage: camerasClient.FetchImage,
+		now:        time.Now,
+		cameras:    make(map[int]cameras.Camera),
+		hashes:     make(map[int][sha256.Size]byte),
+	}
+
+	for _, option := range options {
+		option(archiver)
+	}
+
+	return archiver, nil
+}
+
+func WithInterval(interval time.Duration) ArchiverOption {
+	return func(a *Archiver) {
+		a.interval = interval
+	}
+}
+
+// WithMaxAge removes frames older than maxAge. Zero keeps frames forever.
+func WithMaxAge(maxAge time.Duration) ArchiverOption {
+	return func(a *Archiver) {
+		a.maxAge = maxAge
+	}
+}
+
+// WithMaxBytes removes the oldest frames once the archive grows past maxBytes.
+// Zero disables the size limit.
+func WithMaxBytes(maxBytes int64) ArchiverOption {
+	return func(a *Archiver) {
+		a.maxBytes = maxBytes
+	}
+}
+
+// WithErrorHandler receives the errors Run encounters while polling.
+func WithErrorHandler(handler func(error)) ArchiverOption {
+	return func(a *Archiver) {
+		a.errorHandler = handler
+	}
+}
+
+// Run polls the cameras every interval until ctx is cancelled.
+func (a *Archiver) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.Poll(ctx); err != nil && a.errorHandler != nil {
+			a.errorHandler(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the current frame of every camera once, stores the frames that
+// changed since the last poll and then enforces retention.
+func (a *Archiver) Poll(ctx context.Context) error {
+	var errs []error
+	for _, cameraID := range a.cameraIDs {
+		if err := a.pollCamera(ctx, cameraID); err != nil {
+			errs = append(errs, fmt.Errorf("camera %d: %w", cameraID, err))
+		}
+	}
+
+	if err := a.enforceRetention(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *Archiver) pollCamera(ctx context.Context, cameraID int) error {
+	camera, err := a.camera(cameraID)
+	if err != nil {
+		return err
+	}
+
+	img, err := a.fetchImage(ctx, camera)
+	if err != nil {
+		// the image URL may have moved, so look the camera up again next time
+		a.mu.Lock()
+		delete(a.cameras, cameraID)
+		a.mu.Unlock()
+		return err
+	}
+
+	if img.NotModified {
+		return nil
+	}
+
+	hash := sha256.Sum256(img.Data)
+
+	a.mu.Lock()
+	last, seen := a.hashes[cameraID]
+	a.mu.Unlock()
+
+	if !seen {
+		frames, err := a.Frames(cameraID, time.Time{}, a.now())
+		if err != nil {
+			return err
+		}
+		if len(frames) > 0 {
+			if data, err := os.ReadFile(frames[len(frames)-1].Path); err == nil {
+				last, seen = sha256.Sum256(data), true
+			}
+		}
+	}
+
+	if seen && last == hash {
+		return nil
+	}
+
+	frameTime := a.now()
+	if img.LastModified != nil {
+		frameTime = *img.LastModified
+	}
+
+	if err := a.store(cameraID, frameTime, img); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.hashes[cameraID] = hash
+	a.mu.Unlock()
+
+	return nil
+}
+
+func (a *Archiver) camera(cameraID int) (cameras.Camera, error) {
+	a.mu.Lock()
+	camera, ok := a.cameras[cameraID]
+	a.mu.Unlock()
+	if ok {
+		return camera, nil
+	}
+
+	c, err := a.getCamera(cameraID)
+	if err != nil {
+		return cameras.Camera{}, err
+	}
+	if c == nil {
+		return cameras.Camera{}, fmt.Errorf("camera %d not found", cameraID)
+	}
+
+	a.mu.Lock()
+	a.cameras[cameraID] = *c
+	a.mu.Unlock()
+
+	return *c, nil
+}
+
+func (a *Archiver) store(cameraID int, frameTime time.Time, img *cameras.Image) error {
+	frameTime = frameTime.UTC()
+	dayDir := filepath.Join(a.dir, strconv.Itoa(cameraID), frameTime.Format(dateLayout))
+	if err := os.MkdirAll(dayDir, 0o755); err != nil {
+		return fmt.Errorf("error creating directory: %v", err)
+	}
+
+	path := filepath.Join(dayDir, frameTime.Format(timeLayout)+extension(img.ContentType))
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, img.Data, 0o644); err != nil {
+		return fmt.Errorf("error writing frame: %v", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing frame: %v", err)
+	}
+
+	return nil
+}
+
+func extension(contentType string) string {
+	switch strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ".img"
+	}
+}
+
+// Frames lists the archived frames for the camera taken within [from, to],
+// oldest first. A zero from or to leaves that side of the window open.
+func (a *Archiver) Frames(cameraID int, from, to time.Time) ([]Frame, error) {
+	frames, err := a.cameraFrames(cameraID)
+	if err != nil {
+		return nil, err
+	}
+
+	var inWindow []Frame
+	for _, frame := range frames {
+		if !from.IsZero() && frame.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && frame.Time.After(to) {
+			continue
+		}
+		inWindow = append(inWindow, frame)
+	}
+
+	return inWindow, nil
+}
+
+func (a *Archiver) cameraFrames(cameraID int) ([]Frame, error) {
+	cameraDir := filepath.Join(a.dir, strconv.Itoa(cameraID))
+
+	days, err := os.ReadDir(cameraDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive: %v", err)
+	}
+
+	var frames []Frame
+	for _, day := range days {
+		if !day.IsDir() {
+			continue
+		}
+
+		entries, err := os.ReadDir(filepath.Join(cameraDir, day.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading archive: %v", err)
+		}
+
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || ext == ".tmp" {
+				continue
+			}
+
+			frameTime, err := time.Parse(dateLayout+timeLayout, day.Name()+strings.TrimSuffix(entry.Name(), ext))
+			if err != nil {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+
+			frames = append(frames, Frame{
+				CameraID: cameraID,
+				Time:     frameTime,
+				Path:     filepath.Join(cameraDir, day.Name(), entry.Name()),
+				Size:     info.Size(),
+			})
+		}
+	}
+
+	sort.Slice(frames, func(i, j int) bool {
+		return frames[i].Time.Before(frames[j].Time)
+	})
+
+	return frames, nil
+}
+
+func (a *Archiver) allFrames() ([]Frame, error) {
+	entries, err := os.ReadDir(a.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive: %v", err)
+	}
+
+	var frames []Frame
+	for _, entry := range entries {
+		cameraID, err := strconv.Atoi(entry.Name())
+		if !entry.IsDir() || err != nil {
+			continue
+		}
+
+		cameraFrames, err := a.cameraFrames(cameraID)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, cameraFrames...)
+	}
+
+	sort.Slice(frames, func(i, j int) bool {
+		return frames[i].Time.Before(frames[j].Time)
+	})
+
+	return frames, nil
+}
+
+func (a *Archiver) enforceRetention() error {
+	if a.maxAge <= 0 && a.maxBytes <= 0 {
+		return nil
+	}
+
+	frames, err := a.allFrames()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, frame := range frames {
+		total += frame.Size
+	}
+
+	cutoff := a.now().Add(-a.maxAge)
+
+	var errs []error
+	for _, frame := range frames {
+		expired := a.maxAge > 0 && frame.Time.Before(cutoff)
+		oversized := a.maxBytes > 0 && total > a.maxBytes
+		if !expired && !oversized {
+			break
+		}
+
+		if err := os.Remove(frame.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("error removing frame: %v", err))
+			continue
+		}
+		total -= frame.Size
+
+		// drop the day directory once its last frame is gone
+		_ = os.Remove(filepath.Dir(frame.Path))
+	}
+
+	return errors.Join(errs...)
+}
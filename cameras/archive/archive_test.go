@@ -0,0 +1,92 @@
+package archive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot/cameras"
+)
+
+func newTestArchiver(t *testing.T, frames *[]string, now *time.Time, options ...ArchiverOption) *Archiver {
+	t.Helper()
+
+	archiver := &Archiver{
+		dir:       t.TempDir(),
+		cameraIDs: []int{42},
+		interval:  DefaultInterval,
+		getCamera: func(cameraID int) (*cameras.Camera, error) {
+			return &cameras.Camera{CameraID: cameraID}, nil
+		},
+		fetchImage: func(ctx context.Context, camera cameras.Camera) (*cameras.Image, error) {
+			return &cameras.Image{CameraID: camera.CameraID, Data: []byte((*frames)[0]), ContentType: "image/jpeg"}, nil
+		},
+		now:     func() time.Time { return *now },
+		cameras: make(map[int]cameras.Camera),
+		hashes:  make(map[int][32]byte),
+	}
+
+	for _, option := range options {
+		option(archiver)
+	}
+
+	return archiver
+}
+
+func TestArchiverStoresChangedFrames(t *testing.T) {
+	frames := []string{"frame-1"}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	archiver := newTestArchiver(t, &frames, &now)
+
+	for i, frame := range []string{"frame-1", "frame-1", "frame-2", "frame-2", "frame-3"} {
+		frames[0] = frame
+		now = now.Add(time.Minute)
+		if err := archiver.Poll(context.Background()); err != nil {
+			t.Fatalf("Poll() #%d error = %v", i, err)
+		}
+	}
+
+	got, err := archiver.Frames(42, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Frames() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Frames() returned %d frames, want 3", len(got))
+	}
+
+	window, err := archiver.Frames(42, got[1].Time, got[1].Time)
+	if err != nil {
+		t.Fatalf("Frames() error = %v", err)
+	}
+	if len(window) != 1 || window[0].Path != got[1].Path {
+		t.Errorf("Frames() in window = %v, want only %s", window, got[1].Path)
+	}
+}
+
+func TestArchiverRetention(t *testing.T) {
+	frames := []string{""}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	archiver := newTestArchiver(t, &frames, &now, WithMaxAge(10*time.Minute), WithMaxBytes(20))
+
+	// five 8 byte frames, one every 4 minutes
+	for i := 0; i < 5; i++ {
+		frames[0] = "frame-0" + string(rune('a'+i))
+		now = now.Add(4 * time.Minute)
+		if err := archiver.Poll(context.Background()); err != nil {
+			t.Fatalf("Poll() #%d error = %v", i, err)
+		}
+	}
+
+	got, err := archiver.Frames(42, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Frames() error = %v", err)
+	}
+
+	// the age limit keeps the last three frames, the size limit only two
+	if len(got) != 2 {
+		t.Fatalf("Frames() returned %d frames, want 2", len(got))
+	}
+	if !got[1].Time.Equal(now) {
+		t.Errorf("newest frame at %v, want %v", got[1].Time, now)
+	}
+}
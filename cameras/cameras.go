@@ -17,41 +17,51 @@ const (
 )
 
 type CamerasClient struct {
-	wsdot *wsdot.WSDOTClient
+	wsdot  *wsdot.WSDOTClient
+	images imageCache
 }
 
-func NewCamerasClient(wsdotClient *wsdot.WSDOTClient) (*CamerasClient, error) {
+type CamerasClientOption func(*CamerasClient)
+
+func NewCamerasClient(wsdotClient *wsdot.WSDOTClient, options ...CamerasClientOption) (*CamerasClient, error) {
 	if wsdotClient == nil {
 		return nil, wsdot.ErrNoClient
 	}
 
-	return &CamerasClient{
-		wsdot: wsdotClient,
-	}, nil
+	client := &CamerasClient{
+		wsdot:  wsdotClient,
+		images: imageCache{maxBytes: DefaultImageCacheBytes},
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	return client, nil
 }
 
 type CameraLocation struct {
-	Description any     `json:"Description"`
-	Direction   string  `json:"Direction"`
-	Latitude    float64 `json:"Latitude"`
-	Longitude   float64 `json:"Longitude"`
-	MilePost    int     `json:"MilePost"`
-	RoadName    string  `json:"RoadName"`
+	Description NullableString `json:"Description"`
+	Direction   string         `json:"Direction"`
+	Latitude    float64        `json:"Latitude"`
+	Longitude   float64        `json:"Longitude"`
+	MilePost    int            `json:"MilePost"`
+	RoadName    string         `json:"RoadName"`
 }
 
 type Camera struct {
 	CameraID         int            `json:"CameraID"`
 	CameraLocation   CameraLocation `json:"CameraLocation"`
-	CameraOwner      string         `json:"CameraOwner"`
-	Description      any            `json:"Description"`
+	CameraOwner      NullableString `json:"CameraOwner"`
+	Description      NullableString `json:"Description"`
 	DisplayLatitude  float64        `json:"DisplayLatitude"`
 	DisplayLongitude float64        `json:"DisplayLongitude"`
 	ImageHeight      int            `json:"ImageHeight"`
 	ImageURL         string         `json:"ImageURL"`
 	ImageWidth       int            `json:"ImageWidth"`
 	IsActive         bool           `json:"IsActive"`
-	OwnerURL         string         `json:"OwnerURL"`
-	Region           string         `json:"Region"`
+	OwnerURL         NullableString `json:"OwnerURL"`
+	Region           Region         `json:"Region"`
 	SortOrder        int            `json:"SortOrder"`
 	Title            string         `json:"Title"`
 }
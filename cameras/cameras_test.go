@@ -0,0 +1,114 @@
+package cameras
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeCapturedCameras(t *testing.T) {
+	// testdata/GetCamerasAsJson.json holds synthetic GetCamerasAsJson records
+	// with null, empty and populated descriptions and owners; decoding is
+	// strict so new API fields fail the test
+	payload, err := os.ReadFile(filepath.Join("testdata", "GetCamerasAsJson.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+
+	var cameras []Camera
+	if err := decoder.Decode(&cameras); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	tests := []struct {
+		name                string
+		camera              Camera
+		description         NullableString
+		locationDescription NullableString
+		owner               NullableString
+		ownerURL            NullableString
+	}{
+		{
+			name:                "Null descriptions",
+			camera:              cameras[0],
+			description:         NullableString{},
+			locationDescription: NullableString{},
+		},
+		{
+			name:                "Empty descriptions",
+			camera:              cameras[1],
+			description:         NullableString{},
+			locationDescription: NullableString{},
+			owner:               NullableString{Value: "City of Aberdeen", Valid: true},
+			ownerURL:            NullableString{Value: "https://www.aberdeenwa.gov", Valid: true},
+		},
+		{
+			name:                "Populated descriptions",
+			camera:              cameras[2],
+			description:         NullableString{Value: "Looking east at the summit", Valid: true},
+			locationDescription: NullableString{Value: "Snoqualmie Pass summit", Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.camera.Description != tt.description {
+				t.Errorf("Description = %+v, want %+v", tt.camera.Description, tt.description)
+			}
+			if tt.camera.CameraLocation.Description != tt.locationDescription {
+				t.Errorf("CameraLocation.Description = %+v, want %+v", tt.camera.CameraLocation.Description, tt.locationDescription)
+			}
+			if tt.camera.CameraOwner != tt.owner {
+				t.Errorf("CameraOwner = %+v, want %+v", tt.camera.CameraOwner, tt.owner)
+			}
+			if tt.camera.OwnerURL != tt.ownerURL {
+				t.Errorf("OwnerURL = %+v, want %+v", tt.camera.OwnerURL, tt.ownerURL)
+			}
+		})
+	}
+}
+
+func TestNullableStringJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      NullableString
+		output    string
+		expectErr bool
+	}{
+		{name: "Null", input: `null`, want: NullableString{}, output: `null`},
+		{name: "Empty", input: `""`, want: NullableString{}, output: `null`},
+		{name: "Whitespace", input: `"  "`, want: NullableString{}, output: `null`},
+		{name: "Value", input: `"I-5 at Mercer"`, want: NullableString{Value: "I-5 at Mercer", Valid: true}, output: `"I-5 at Mercer"`},
+		{name: "Number", input: `42`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got NullableString
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Unmarshal() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+
+			output, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(output) != tt.output {
+				t.Errorf("Marshal() = %s, want %s", output, tt.output)
+			}
+		})
+	}
+}
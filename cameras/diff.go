@@ -0,0 +1,228 @@
+package cameras
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"alpineworks.io/wsdot"
+)
+
+const (
+	DefaultChangeWatcherInterval = 15 * time.Minute
+)
+
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+type CameraChange struct {
+	CameraID int
+	Old      Camera
+	New      Camera
+	Fields   []FieldChange
+}
+
+// Moved reports whether the camera's actual or display coordinates changed.
+func (c CameraChange) Moved() bool {
+	for _, field := range c.Fields {
+		switch field.Field {
+		case "CameraLocation.Latitude", "CameraLocation.Longitude", "DisplayLatitude", "DisplayLongitude":
+			return true
+		}
+	}
+
+	return false
+}
+
+// Deactivated reports whether the camera went from active to inactive.
+func (c CameraChange) Deactivated() bool {
+	return c.Old.IsActive && !c.New.IsActive
+}
+
+type CameraDiff struct {
+	Added   []Camera
+	Removed []Camera
+	Changed []CameraChange
+}
+
+func (d CameraDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffCameras compares two GetCameras snapshots keyed by CameraID. Results are
+// ordered by CameraID.
+func DiffCameras(before, after []Camera) CameraDiff {
+	oldByID := make(map[int]Camera, len(before))
+	for _, camera := range before {
+		oldByID[camera.CameraID] = camera
+	}
+
+	newByID := make(map[int]Camera, len(after))
+	for _, camera := range after {
+		newByID[camera.CameraID] = camera
+	}
+
+	var diff CameraDiff
+	for id, camera := range newByID {
+		previous, ok := oldByID[id]
+		if !ok {
+			diff.Added = append(diff.Added, camera)
+			continue
+		}
+
+		if fields := diffCameraFields(previous, camera); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, CameraChange{CameraID: id, Old: previous, New: camera, Fields: fields})
+		}
+	}
+
+	for id, camera := range oldByID {
+		if _, ok := newByID[id]; !ok {
+			diff.Removed = append(diff.Removed, camera)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].CameraID < diff.Added[j].CameraID })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].CameraID < diff.Removed[j].CameraID })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].CameraID < diff.Changed[j].CameraID })
+
+	return diff
+}
+
+func diffCameraFields(before, after Camera) []FieldChange {
+	var changes []FieldChange
+	compare := func(field string, a, b any) {
+		if a != b {
+			changes = append(changes, FieldChange{Field: field, Old: fmt.Sprint(a), New: fmt.Sprint(b)})
+		}
+	}
+
+	compare("CameraLocation.Description", before.CameraLocation.Description.Value, after.CameraLocation.Description.Value)
+	compare("CameraLocation.Direction", before.CameraLocation.Direction, after.CameraLocation.Direction)
+	compare("CameraLocation.Latitude", before.CameraLocation.Latitude, after.CameraLocation.Latitude)
+	compare("CameraLocation.Longitude", before.CameraLocation.Longitude, after.CameraLocation.Longitude)
+	compare("CameraLocation.MilePost", before.CameraLocation.MilePost, after.CameraLocation.MilePost)
+	compare("CameraLocation.RoadName", before.CameraLocation.RoadName, after.CameraLocation.RoadName)
+	compare("CameraOwner", before.CameraOwner.Value, after.CameraOwner.Value)
+	compare("Description", before.Description.Value, after.Description.Value)
+	compare("DisplayLatitude", before.DisplayLatitude, after.DisplayLatitude)
+	compare("DisplayLongitude", before.DisplayLongitude, after.DisplayLongitude)
+	compare("ImageHeight", before.ImageHeight, after.ImageHeight)
+	compare("ImageURL", before.ImageURL, after.ImageURL)
+	compare("ImageWidth", before.ImageWidth, after.ImageWidth)
+	compare("IsActive", before.IsActive, after.IsActive)
+	compare("OwnerURL", before.OwnerURL.Value, after.OwnerURL.Value)
+	compare("Region", before.Region, after.Region)
+	compare("SortOrder", before.SortOrder, after.SortOrder)
+	compare("Title", before.Title, after.Title)
+
+	return changes
+}
+
+// ChangeWatcher polls GetCameras and delivers a CameraDiff whenever the
+// catalog changes. The first poll only records a baseline unless an initial
+// snapshot is given with WithInitialCameras, in which case it is diffed
+// against that snapshot.
+type ChangeWatcher struct {
+	interval     time.Duration
+	errorHandler func(error)
+	previous     []Camera
+	hasPrevious  bool
+
+	getCameras func() ([]Camera, error)
+
+	changes chan CameraDiff
+}
+
+type ChangeWatcherOption func(*ChangeWatcher)
+
+func NewChangeWatcher(camerasClient *CamerasClient, options ...ChangeWatcherOption) (*ChangeWatcher, error) {
+	if camerasClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	watcher := &ChangeWatcher{
+		interval:   DefaultChangeWatcherInterval,
+		getCameras: camerasClient.GetCameras,
+		changes:    make(chan CameraDiff),
+	}
+
+	for _, option := range options {
+		option(watcher)
+	}
+
+	return watcher, nil
+}
+
+func WithChangeWatcherInterval(interval time.Duration) ChangeWatcherOption {
+	return func(w *ChangeWatcher) {
+		w.interval = interval
+	}
+}
+
+func WithChangeWatcherErrorHandler(handler func(error)) ChangeWatcherOption {
+	return func(w *ChangeWatcher) {
+		w.errorHandler = handler
+	}
+}
+
+// WithInitialCameras diffs the first poll against a previously stored catalog.
+func WithInitialCameras(cameras []Camera) ChangeWatcherOption {
+	return func(w *ChangeWatcher) {
+		w.previous, w.hasPrevious = cameras, true
+	}
+}
+
+// Changes returns the channel diffs are delivered on. It is closed when Run
+// returns.
+func (w *ChangeWatcher) Changes() <-chan CameraDiff {
+	return w.changes
+}
+
+// Run polls every interval until ctx is cancelled.
+func (w *ChangeWatcher) Run(ctx context.Context) error {
+	defer close(w.changes)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.poll(ctx); err != nil && w.errorHandler != nil {
+			w.errorHandler(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ChangeWatcher) poll(ctx context.Context) error {
+	cameras, err := w.getCameras()
+	if err != nil {
+		return err
+	}
+
+	if !w.hasPrevious {
+		w.previous, w.hasPrevious = cameras, true
+		return nil
+	}
+
+	diff := DiffCameras(w.previous, cameras)
+	w.previous = cameras
+	if diff.Empty() {
+		return nil
+	}
+
+	select {
+	case w.changes <- diff:
+	case <-ctx.Done():
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package cameras
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDiffCameras(t *testing.T) {
+	base := []Camera{
+		{CameraID: 1, Title: "I-5 at Mercer", IsActive: true, CameraLocation: CameraLocation{Latitude: 47.62, Longitude: -122.33}},
+		{CameraID: 2, Title: "SR 520 at Montlake", IsActive: true},
+		{CameraID: 3, Title: "I-90 at Snoqualmie Pass", IsActive: true},
+	}
+
+	with := func(modify func([]Camera) []Camera) []Camera {
+		cameras := append([]Camera(nil), base...)
+		return modify(cameras)
+	}
+
+	tests := []struct {
+		name            string
+		after           []Camera
+		wantAdded       []int
+		wantRemoved     []int
+		wantChanged     []int
+		wantFields      []string
+		wantMoved       bool
+		wantDeactivated bool
+	}{
+		{
+			name:  "Unchanged",
+			after: base,
+		},
+		{
+			name:      "Added",
+			after:     with(func(c []Camera) []Camera { return append(c, Camera{CameraID: 4}) }),
+			wantAdded: []int{4},
+		},
+		{
+			name:        "Removed",
+			after:       with(func(c []Camera) []Camera { return c[1:] }),
+			wantRemoved: []int{1},
+		},
+		{
+			name:        "Retitled",
+			after:       with(func(c []Camera) []Camera { c[1].Title = "SR 520 at Montlake Blvd"; return c }),
+			wantChanged: []int{2},
+			wantFields:  []string{"Title"},
+		},
+		{
+			name: "Moved",
+			after: with(func(c []Camera) []Camera {
+				c[0].CameraLocation.Latitude = 47.63
+				return c
+			}),
+			wantChanged: []int{1},
+			wantFields:  []string{"CameraLocation.Latitude"},
+			wantMoved:   true,
+		},
+		{
+			name:            "Deactivated",
+			after:           with(func(c []Camera) []Camera { c[2].IsActive = false; return c }),
+			wantChanged:     []int{3},
+			wantFields:      []string{"IsActive"},
+			wantDeactivated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffCameras(base, tt.after)
+
+			if got := cameraIDs(diff.Added); !equalIDs(got, tt.wantAdded) {
+				t.Errorf("DiffCameras() added = %v, want %v", got, tt.wantAdded)
+			}
+			if got := cameraIDs(diff.Removed); !equalIDs(got, tt.wantRemoved) {
+				t.Errorf("DiffCameras() removed = %v, want %v", got, tt.wantRemoved)
+			}
+
+			var changed []int
+			for _, change := range diff.Changed {
+				changed = append(changed, change.CameraID)
+			}
+			if !equalIDs(changed, tt.wantChanged) {
+				t.Fatalf("DiffCameras() changed = %v, want %v", changed, tt.wantChanged)
+			}
+
+			if diff.Empty() != (tt.wantAdded == nil && tt.wantRemoved == nil && tt.wantChanged == nil) {
+				t.Errorf("DiffCameras() Empty() = %v", diff.Empty())
+			}
+
+			if len(diff.Changed) == 0 {
+				return
+			}
+
+			change := diff.Changed[0]
+			var fields []string
+			for _, field := range change.Fields {
+				fields = append(fields, field.Field)
+			}
+			if len(fields) != len(tt.wantFields) || (len(fields) > 0 && fields[0] != tt.wantFields[0]) {
+				t.Errorf("DiffCameras() fields = %v, want %v", fields, tt.wantFields)
+			}
+
+			if change.Moved() != tt.wantMoved {
+				t.Errorf("Moved() = %v, want %v", change.Moved(), tt.wantMoved)
+			}
+			if change.Deactivated() != tt.wantDeactivated {
+				t.Errorf("Deactivated() = %v, want %v", change.Deactivated(), tt.wantDeactivated)
+			}
+		})
+	}
+}
+
+func TestChangeWatcherPoll(t *testing.T) {
+	polls := [][]Camera{
+		{{CameraID: 1, IsActive: true}},
+		{{CameraID: 1, IsActive: true}},
+		{{CameraID: 1, IsActive: false}, {CameraID: 2}},
+	}
+
+	poll := 0
+	watcher := &ChangeWatcher{
+		getCameras: func() ([]Camera, error) { return polls[poll], nil },
+		changes:    make(chan CameraDiff, 1),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for poll = range polls {
+		if err := watcher.poll(ctx); err != nil {
+			t.Fatalf("poll() error = %v", err)
+		}
+
+		if poll < 2 && len(watcher.changes) != 0 {
+			t.Fatalf("poll() %d delivered a diff, want none", poll)
+		}
+	}
+
+	diff := <-watcher.changes
+	if !equalIDs(cameraIDs(diff.Added), []int{2}) || len(diff.Changed) != 1 || !diff.Changed[0].Deactivated() {
+		t.Errorf("poll() diff = %+v, want camera 2 added and camera 1 deactivated", diff)
+	}
+}
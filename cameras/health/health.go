@@ -0,0 +1,271 @@
+// Package health watches camera images and reports cameras that have gone
+// stale or broken even though the API still lists them as active.
+package health
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/cameras"
+)
+
+const (
+	DefaultInterval      = time.Minute
+	DefaultStaleAfter    = 15 * time.Minute
+	DefaultMinImageBytes = 1024
+	DefaultEventBuffer   = 64
+)
+
+type State int
+
+const (
+	StateUnknown State = iota
+	StateHealthy
+	StateStale
+	StateBroken
+)
+
+func (s State) String() string {
+	switch s {
+	case StateHealthy:
+		return "healthy"
+	case StateStale:
+		return "stale"
+	case StateBroken:
+		return "broken"
+	default:
+		return "unknown"
+	}
+}
+
+type Status struct {
+	CameraID     int
+	State        State
+	Reason       string
+	LastChecked  time.Time
+	LastChanged  time.Time
+	LastModified *time.Time
+}
+
+// Event is emitted whenever a camera moves from one state to another.
+type Event struct {
+	Camera   cameras.Camera
+	Previous State
+	Status   Status
+}
+
+type cameraState struct {
+	status Status
+	hash   [sha256.Size]byte
+	seen   bool
+}
+
+type Monitor struct {
+	cameras       []cameras.Camera
+	interval      time.Duration
+	staleAfter    time.Duration
+	minImageBytes int
+	placeholders  map[[sha256.Size]byte]struct{}
+
+	fetchImage func(ctx context.Context, camera cameras.Camera) (*cameras.Image, error)
+	now        func() time.Time
+
+	events chan Event
+
+	mu     sync.Mutex
+	states map[int]*cameraState
+}
+
+type MonitorOption func(*Monitor)
+
+func NewMonitor(camerasClient *cameras.CamerasClient, cams []cameras.Camera, options ...MonitorOption) (*Monitor, error) {
+	if camerasClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	monitor := &Monitor{
+		cameras:       cams,
+		interval:      DefaultInterval,
+		staleAfter:    DefaultStaleAfter,
+		minImageBytes: DefaultMinImageBytes,
+		placeholders:  make(map[[sha256.Size]byte]struct{}),
+		fetchImage:    camerasClient.FetchImage,
+		now:           time.Now,
+		events:        make(chan Event, DefaultEventBuffer),
+		states:        make(map[int]*cameraState),
+	}
+
+	for _, option := range options {
+		option(monitor)
+	}
+
+	return monitor, nil
+}
+
+func WithInterval(interval time.Duration) MonitorOption {
+	return func(m *Monitor) {
+		m.interval = interval
+	}
+}
+
+// WithStaleAfter sets how long a frame may go unchanged, or how old its
+// Last-Modified time may be, before the camera is reported stale.
+func WithStaleAfter(staleAfter time.Duration) MonitorOption {
+	return func(m *Monitor) {
+		m.staleAfter = staleAfter
+	}
+}
+
+// WithMinImageBytes reports images smaller than minImageBytes as broken.
+func WithMinImageBytes(minImageBytes int) MonitorOption {
+	return func(m *Monitor) {
+		m.minImageBytes = minImageBytes
+	}
+}
+
+// WithPlaceholderImages reports cameras serving any of the given images, such
+// as the "camera offline" frames WSDOT substitutes, as broken.
+func WithPlaceholderImages(images ...[]byte) MonitorOption {
+	return func(m *Monitor) {
+		for _, image := range images {
+			m.placeholders[sha256.Sum256(image)] = struct{}{}
+		}
+	}
+}
+
+func WithEventBuffer(size int) MonitorOption {
+	return func(m *Monitor) {
+		m.events = make(chan Event, size)
+	}
+}
+
+// Events returns the channel state changes are delivered on. It is closed
+// when Run returns. Events must be drained, otherwise checks block.
+func (m *Monitor) Events() <-chan Event {
+	return m.events
+}
+
+// Run checks every camera each interval until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) error {
+	defer close(m.events)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.check(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// check fetches every camera once and emits events for the cameras whose
+// state changed. It only returns an error when ctx is cancelled. It is only
+// called from Run, which owns the events channel.
+func (m *Monitor) check(ctx context.Context) error {
+	for _, camera := range m.cameras {
+		img, err := m.fetchImage(ctx, camera)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		previous, status := m.update(camera, img, err)
+
+		if previous == status.State {
+			continue
+		}
+
+		select {
+		case m.events <- Event{Camera: camera, Previous: previous, Status: status}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (m *Monitor) update(camera cameras.Camera, img *cameras.Image, err error) (State, Status) {
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[camera.CameraID]
+	if !ok {
+		state = &cameraState{status: Status{CameraID: camera.CameraID, LastChanged: now}}
+		m.states[camera.CameraID] = state
+	}
+
+	previous := state.status.State
+	state.status.LastChecked = now
+	state.status.State, state.status.Reason = m.evaluate(state, img, err, now)
+
+	return previous, state.status
+}
+
+func (m *Monitor) evaluate(state *cameraState, img *cameras.Image, err error, now time.Time) (State, string) {
+	switch {
+	case errors.Is(err, cameras.ErrEmptyImage):
+		return StateBroken, "empty image"
+	case err != nil:
+		return StateBroken, err.Error()
+	}
+
+	if img.LastModified != nil {
+		state.status.LastModified = img.LastModified
+	}
+
+	if !img.NotModified {
+		if len(img.Data) < m.minImageBytes {
+			return StateBroken, fmt.Sprintf("image is only %d bytes", len(img.Data))
+		}
+
+		hash := sha256.Sum256(img.Data)
+		if _, placeholder := m.placeholders[hash]; placeholder {
+			return StateBroken, "placeholder image"
+		}
+
+		if !state.seen || hash != state.hash {
+			state.hash, state.seen = hash, true
+			state.status.LastChanged = now
+		}
+	}
+
+	if m.staleAfter > 0 {
+		if lastModified := state.status.LastModified; lastModified != nil && now.Sub(*lastModified) > m.staleAfter {
+			return StateStale, fmt.Sprintf("last modified %s ago", now.Sub(*lastModified).Round(time.Second))
+		}
+
+		if now.Sub(state.status.LastChanged) > m.staleAfter {
+			return StateStale, fmt.Sprintf("frame unchanged for %s", now.Sub(state.status.LastChanged).Round(time.Second))
+		}
+	}
+
+	return StateHealthy, ""
+}
+
+// Statuses returns the latest status of every camera checked so far, keyed by
+// camera ID.
+func (m *Monitor) Statuses() map[int]Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make(map[int]Status, len(m.states))
+	for cameraID, state := range m.states {
+		statuses[cameraID] = state.status
+	}
+
+	return statuses
+}
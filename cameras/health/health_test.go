@@ -0,0 +1,118 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot/cameras"
+)
+
+func TestMonitorStates(t *testing.T) {
+	placeholder := bytes.Repeat([]byte{0xff}, 2048)
+	frameA := bytes.Repeat([]byte{0x01}, 2048)
+	frameB := bytes.Repeat([]byte{0x02}, 2048)
+
+	type response struct {
+		data []byte
+		err  error
+	}
+
+	steps := []struct {
+		name     string
+		response response
+		advance  time.Duration
+		want     State
+		emitted  bool
+	}{
+		{name: "first frame", response: response{data: frameA}, want: StateHealthy, emitted: true},
+		{name: "same frame soon after", response: response{data: frameA}, advance: 5 * time.Minute, want: StateHealthy},
+		{name: "same frame too long", response: response{data: frameA}, advance: 11 * time.Minute, want: StateStale, emitted: true},
+		{name: "new frame", response: response{data: frameB}, advance: time.Minute, want: StateHealthy, emitted: true},
+		{name: "http error", response: response{err: errors.New("unexpected status code: 500")}, advance: time.Minute, want: StateBroken, emitted: true},
+		{name: "placeholder", response: response{data: placeholder}, advance: time.Minute, want: StateBroken},
+		{name: "tiny image", response: response{data: []byte{0x01}}, advance: time.Minute, want: StateBroken},
+		{name: "recovered", response: response{data: frameA}, advance: time.Minute, want: StateHealthy, emitted: true},
+	}
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	current := steps[0].response
+
+	monitor := &Monitor{
+		cameras:       []cameras.Camera{{CameraID: 7}},
+		staleAfter:    15 * time.Minute,
+		minImageBytes: DefaultMinImageBytes,
+		placeholders:  make(map[[32]byte]struct{}),
+		fetchImage: func(ctx context.Context, camera cameras.Camera) (*cameras.Image, error) {
+			if current.err != nil {
+				return nil, current.err
+			}
+			return &cameras.Image{CameraID: camera.CameraID, Data: current.data}, nil
+		},
+		now:    func() time.Time { return now },
+		events: make(chan Event, len(steps)),
+		states: make(map[int]*cameraState),
+	}
+	WithPlaceholderImages(placeholder)(monitor)
+
+	for _, step := range steps {
+		current = step.response
+		now = now.Add(step.advance)
+
+		if err := monitor.check(context.Background()); err != nil {
+			t.Fatalf("%s: check() error = %v", step.name, err)
+		}
+
+		if got := monitor.Statuses()[7].State; got != step.want {
+			t.Errorf("%s: state = %s, want %s", step.name, got, step.want)
+		}
+
+		select {
+		case event := <-monitor.Events():
+			if !step.emitted {
+				t.Errorf("%s: unexpected event %s -> %s", step.name, event.Previous, event.Status.State)
+			}
+		default:
+			if step.emitted {
+				t.Errorf("%s: no event emitted", step.name)
+			}
+		}
+	}
+}
+
+func TestMonitorRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	monitor := &Monitor{
+		cameras:  []cameras.Camera{{CameraID: 7}},
+		interval: time.Hour,
+		fetchImage: func(ctx context.Context, camera cameras.Camera) (*cameras.Image, error) {
+			return nil, cameras.ErrEmptyImage
+		},
+		now:          time.Now,
+		placeholders: make(map[[32]byte]struct{}),
+		events:       make(chan Event, 1),
+		states:       make(map[int]*cameraState),
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- monitor.Run(ctx)
+	}()
+
+	event := <-monitor.Events()
+	if event.Status.State != StateBroken {
+		t.Errorf("Run() event state = %s, want %s", event.Status.State, StateBroken)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+	if _, ok := <-monitor.Events(); ok {
+		t.Errorf("Events() not closed after Run returned")
+	}
+}
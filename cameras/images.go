@@ -0,0 +1,201 @@
+package cameras
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultImageCacheBytes bounds the total size of the frames FetchImage
+	// keeps for conditional requests.
+	DefaultImageCacheBytes = 64 << 20
+)
+
+var (
+	ErrEmptyImage             = errors.New("empty image")
+	ErrImageDimensionMismatch = errors.New("image dimensions do not match camera")
+)
+
+type Image struct {
+	CameraID     int
+	Data         []byte
+	ContentType  string
+	LastModified *time.Time
+	ETag         string
+	Width        int
+	Height       int
+	// NotModified is set when the server confirmed that the frame has not
+	// changed since the previous fetch; Data holds the previously fetched frame.
+	NotModified bool
+	// DimensionMismatch is set when the frame's size differs from the camera's
+	// ImageWidth and ImageHeight, usually because the camera metadata is stale.
+	DimensionMismatch bool
+}
+
+// CheckDimensions returns ErrImageDimensionMismatch when the frame's size does
+// not match the camera's ImageWidth and ImageHeight, for callers that treat
+// stale camera metadata as an error.
+func (i *Image) CheckDimensions(camera Camera) error {
+	if (camera.ImageWidth > 0 && camera.ImageWidth != i.Width) || (camera.ImageHeight > 0 && camera.ImageHeight != i.Height) {
+		return fmt.Errorf("%w: got %dx%d, want %dx%d", ErrImageDimensionMismatch, i.Width, i.Height, camera.ImageWidth, camera.ImageHeight)
+	}
+
+	return nil
+}
+
+// WithImageCacheBytes sets the total size of the frames FetchImage keeps for
+// conditional requests. The oldest frames are dropped first; zero disables
+// the cache.
+func WithImageCacheBytes(maxBytes int) CamerasClientOption {
+	return func(c *CamerasClient) {
+		c.images.maxBytes = maxBytes
+	}
+}
+
+type imageCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	images   map[string]*Image
+	// order holds the cached URLs, least recently stored first
+	order []string
+}
+
+func (c *imageCache) get(url string) *Image {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.images[url]
+}
+
+func (c *imageCache) put(url string, img *Image) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.images == nil {
+		c.images = make(map[string]*Image)
+	}
+
+	c.remove(url)
+
+	if len(img.Data) > c.maxBytes {
+		return
+	}
+
+	for c.size+len(img.Data) > c.maxBytes {
+		c.remove(c.order[0])
+	}
+
+	c.images[url] = img
+	c.order = append(c.order, url)
+	c.size += len(img.Data)
+}
+
+// remove drops the frame cached for url, if any. c.mu must be held.
+func (c *imageCache) remove(url string) {
+	img, ok := c.images[url]
+	if !ok {
+		return
+	}
+
+	delete(c.images, url)
+	c.order = slices.DeleteFunc(c.order, func(u string) bool { return u == url })
+	c.size -= len(img.Data)
+}
+
+// FetchImage downloads the current frame for the camera. Frames are fetched
+// with If-None-Match and If-Modified-Since based on the previous fetch of the
+// same URL, so unchanged frames are not downloaded again. The last frame of
+// each URL is kept for this, up to the client's image cache size.
+//
+// A frame whose size differs from the camera's metadata is still returned,
+// with DimensionMismatch set; use CheckDimensions to reject it instead.
+func (c *CamerasClient) FetchImage(ctx context.Context, camera Camera) (*Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, camera.ImageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	previous := c.images.get(camera.ImageURL)
+	if previous != nil {
+		if previous.ETag != "" {
+			req.Header.Set("If-None-Match", previous.ETag)
+		}
+		if previous.LastModified != nil {
+			req.Header.Set("If-Modified-Since", previous.LastModified.UTC().Format(http.TimeFormat))
+		}
+	}
+
+	resp, err := c.wsdot.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && previous != nil {
+		// the cached frame is shared, so callers get their own copy
+		img := *previous
+		img.Data = bytes.Clone(previous.Data)
+		img.NotModified = true
+		return &img, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+
+	if len(data) == 0 {
+		return nil, ErrEmptyImage
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %v", err)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "image/" + format
+	}
+
+	var lastModified *time.Time
+	if header := resp.Header.Get("Last-Modified"); header != "" {
+		if t, err := http.ParseTime(header); err == nil {
+			lastModified = &t
+		}
+	}
+
+	img := &Image{
+		CameraID:     camera.CameraID,
+		Data:         data,
+		ContentType:  contentType,
+		LastModified: lastModified,
+		ETag:         resp.Header.Get("ETag"),
+		Width:        config.Width,
+		Height:       config.Height,
+	}
+
+	img.DimensionMismatch = img.CheckDimensions(camera) != nil
+
+	cached := *img
+	cached.Data = bytes.Clone(data)
+	c.images.put(camera.ImageURL, &cached)
+
+	return img, nil
+}
@@ -0,0 +1,229 @@
+package cameras
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot"
+)
+
+func newTestImageServer(t *testing.T, width, height int) (*httptest.Server, *int) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("error encoding image: %v", err)
+	}
+
+	lastModified := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	downloads := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"frame-1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		downloads++
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("ETag", `"frame-1"`)
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		_, _ = w.Write(buf.Bytes())
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &downloads
+}
+
+func TestFetchImage(t *testing.T) {
+	server, downloads := newTestImageServer(t, 4, 3)
+
+	wsdotClient, err := wsdot.NewWSDOTClient(wsdot.WithAPIKey("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewCamerasClient(wsdotClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	camera := Camera{CameraID: 1, ImageURL: server.URL, ImageWidth: 4, ImageHeight: 3}
+
+	first, err := client.FetchImage(context.Background(), camera)
+	if err != nil {
+		t.Fatalf("FetchImage() error = %v", err)
+	}
+	if first.NotModified || first.Width != 4 || first.Height != 3 || first.ContentType != "image/png" || first.LastModified == nil {
+		t.Errorf("FetchImage() = %+v, want a fresh 4x3 png with Last-Modified", first)
+	}
+
+	second, err := client.FetchImage(context.Background(), camera)
+	if err != nil {
+		t.Fatalf("FetchImage() error = %v", err)
+	}
+	if !second.NotModified || !bytes.Equal(second.Data, first.Data) {
+		t.Errorf("FetchImage() NotModified = %v, want the cached frame", second.NotModified)
+	}
+	if *downloads != 1 {
+		t.Errorf("image downloaded %d times, want 1", *downloads)
+	}
+
+	if first.DimensionMismatch {
+		t.Errorf("FetchImage() DimensionMismatch = true, want false")
+	}
+}
+
+func TestFetchImageDimensionMismatch(t *testing.T) {
+	server, downloads := newTestImageServer(t, 4, 3)
+
+	wsdotClient, err := wsdot.NewWSDOTClient(wsdot.WithAPIKey("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewCamerasClient(wsdotClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	camera := Camera{CameraID: 1, ImageURL: server.URL, ImageWidth: 640, ImageHeight: 480}
+
+	first, err := client.FetchImage(context.Background(), camera)
+	if err != nil {
+		t.Fatalf("FetchImage() error = %v", err)
+	}
+	if !first.DimensionMismatch || first.Width != 4 || first.Height != 3 {
+		t.Errorf("FetchImage() = %+v, want the 4x3 frame flagged as mismatched", first)
+	}
+	if err := first.CheckDimensions(camera); !errors.Is(err, ErrImageDimensionMismatch) {
+		t.Errorf("CheckDimensions() error = %v, want %v", err, ErrImageDimensionMismatch)
+	}
+
+	// the mismatched frame is cached like any other
+	second, err := client.FetchImage(context.Background(), camera)
+	if err != nil {
+		t.Fatalf("FetchImage() error = %v", err)
+	}
+	if !second.NotModified || *downloads != 1 {
+		t.Errorf("FetchImage() NotModified = %v after %d downloads, want the cached frame", second.NotModified, *downloads)
+	}
+}
+
+func TestFetchImageCopiesCachedFrame(t *testing.T) {
+	server, _ := newTestImageServer(t, 4, 3)
+
+	wsdotClient, err := wsdot.NewWSDOTClient(wsdot.WithAPIKey("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewCamerasClient(wsdotClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	camera := Camera{CameraID: 1, ImageURL: server.URL}
+
+	first, err := client.FetchImage(context.Background(), camera)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := bytes.Clone(first.Data)
+
+	// callers that modify a frame must not change what later fetches return
+	clear(first.Data)
+	second, err := client.FetchImage(context.Background(), camera)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clear(second.Data)
+
+	third, err := client.FetchImage(context.Background(), camera)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !third.NotModified || !bytes.Equal(third.Data, original) {
+		t.Errorf("FetchImage() returned a modified frame from the cache")
+	}
+}
+
+func TestImageCacheBound(t *testing.T) {
+	frame := func(size int) *Image {
+		return &Image{Data: make([]byte, size)}
+	}
+
+	tests := []struct {
+		name     string
+		maxBytes int
+		puts     []string
+		sizes    []int
+		want     []string
+	}{
+		{
+			name:     "Fits",
+			maxBytes: 100,
+			puts:     []string{"a", "b", "c"},
+			sizes:    []int{30, 30, 40},
+			want:     []string{"a", "b", "c"},
+		},
+		{
+			name:     "Oldest frames are dropped",
+			maxBytes: 100,
+			puts:     []string{"a", "b", "c"},
+			sizes:    []int{40, 40, 50},
+			want:     []string{"b", "c"},
+		},
+		{
+			name:     "Replacing a frame refreshes it",
+			maxBytes: 100,
+			puts:     []string{"a", "b", "a", "c"},
+			sizes:    []int{40, 40, 40, 50},
+			want:     []string{"a", "c"},
+		},
+		{
+			name:     "Frame larger than the cache",
+			maxBytes: 100,
+			puts:     []string{"a", "b"},
+			sizes:    []int{40, 200},
+			want:     []string{"a"},
+		},
+		{
+			name:     "Disabled",
+			maxBytes: 0,
+			puts:     []string{"a"},
+			sizes:    []int{1},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := imageCache{maxBytes: tt.maxBytes}
+			for i, url := range tt.puts {
+				cache.put(url, frame(tt.sizes[i]))
+			}
+
+			var got []string
+			size := 0
+			for _, url := range []string{"a", "b", "c"} {
+				if img := cache.get(url); img != nil {
+					got = append(got, url)
+					size += len(img.Data)
+				}
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("cached = %v, want %v", got, tt.want)
+			}
+			if cache.size != size || size > tt.maxBytes {
+				t.Errorf("cache size = %d, holding %d bytes, limit %d", cache.size, size, tt.maxBytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cameras
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// NullableString holds a string field the API returns as either null or a
+// string. Null, empty and whitespace-only values all decode to an invalid
+// NullableString, so callers only need to check Valid.
+type NullableString struct {
+	Value string
+	Valid bool
+}
+
+func NewNullableString(value string) NullableString {
+	value = strings.TrimSpace(value)
+
+	return NullableString{
+		Value: value,
+		Valid: value != "",
+	}
+}
+
+func (n NullableString) String() string {
+	return n.Value
+}
+
+func (n *NullableString) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = NullableString{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("error decoding nullable string: %v", err)
+	}
+
+	*n = NewNullableString(value)
+
+	return nil
+}
+
+func (n NullableString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.Value)
+}
@@ -0,0 +1,204 @@
+package cameras
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type Region string
+
+const (
+	RegionNorthwest    Region = "NW"
+	RegionNorthCentral Region = "NC"
+	RegionOlympic      Region = "OL"
+	RegionSouthwest    Region = "SW"
+	RegionSouthCentral Region = "SC"
+	RegionEastern      Region = "ER"
+)
+
+func (r Region) String() string {
+	switch r {
+	case RegionNorthwest:
+		return "Northwest"
+	case RegionNorthCentral:
+		return "North Central"
+	case RegionOlympic:
+		return "Olympic"
+	case RegionSouthwest:
+		return "Southwest"
+	case RegionSouthCentral:
+		return "South Central"
+	case RegionEastern:
+		return "Eastern"
+	default:
+		return string(r)
+	}
+}
+
+// NormalizeRoadName converts the road names used across WSDOT APIs to the
+// three digit state route form, so "I-5", "I 5", "SR 5" and "005" all become
+// "005". Any suffix after the route number is kept, uppercased, e.g.
+// "SR 20 Spur" becomes "020SPUR". Names without a route number are only
+// uppercased and trimmed.
+func NormalizeRoadName(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	start := strings.IndexFunc(name, unicode.IsDigit)
+	if start < 0 {
+		return name
+	}
+
+	end := start
+	for end < len(name) && unicode.IsDigit(rune(name[end])) {
+		end++
+	}
+
+	number := strings.TrimLeft(name[start:end], "0")
+	if number == "" {
+		number = "0"
+	}
+	if len(number) < 3 {
+		number = strings.Repeat("0", 3-len(number)) + number
+	}
+
+	suffix := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name[end:])
+
+	return number + suffix
+}
+
+// normalizeDirection maps the direction spellings used by WSDOT and people
+// ("N", "NB", "northbound") to the single letter form the cameras API uses.
+func normalizeDirection(direction string) string {
+	direction = strings.ToUpper(strings.TrimSpace(direction))
+	if direction == "" {
+		return ""
+	}
+
+	switch direction[0] {
+	case 'N', 'S', 'E', 'W', 'B':
+		return direction[:1]
+	default:
+		return direction
+	}
+}
+
+// Query selects cameras by their road, location and status. The zero value
+// matches every camera; each method narrows the query and returns it so calls
+// can be chained.
+type Query struct {
+	roads       []string
+	directions  []string
+	regions     []Region
+	owners      []string
+	minMilePost *float64
+	maxMilePost *float64
+	activeOnly  bool
+}
+
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Road matches cameras on any of the given roads. Names are compared with
+// NormalizeRoadName.
+func (q *Query) Road(names ...string) *Query {
+	for _, name := range names {
+		q.roads = append(q.roads, NormalizeRoadName(name))
+	}
+
+	return q
+}
+
+// MilePostBetween matches cameras between the two mileposts, inclusive.
+func (q *Query) MilePostBetween(from, to float64) *Query {
+	if from > to {
+		from, to = to, from
+	}
+	q.minMilePost, q.maxMilePost = &from, &to
+
+	return q
+}
+
+// Direction matches cameras facing any of the given directions. Cameras that
+// cover both directions always match.
+func (q *Query) Direction(directions ...string) *Query {
+	for _, direction := range directions {
+		q.directions = append(q.directions, normalizeDirection(direction))
+	}
+
+	return q
+}
+
+func (q *Query) Region(regions ...Region) *Query {
+	q.regions = append(q.regions, regions...)
+
+	return q
+}
+
+// Owner matches cameras owned by any of the given owners, ignoring case.
+func (q *Query) Owner(owners ...string) *Query {
+	for _, owner := range owners {
+		q.owners = append(q.owners, strings.ToUpper(strings.TrimSpace(owner)))
+	}
+
+	return q
+}
+
+func (q *Query) Active() *Query {
+	q.activeOnly = true
+
+	return q
+}
+
+func (q *Query) Match(camera Camera) bool {
+	if q.activeOnly && !camera.IsActive {
+		return false
+	}
+
+	if len(q.roads) > 0 && !slices.Contains(q.roads, NormalizeRoadName(camera.CameraLocation.RoadName)) {
+		return false
+	}
+
+	milePost := float64(camera.CameraLocation.MilePost)
+	if q.minMilePost != nil && milePost < *q.minMilePost {
+		return false
+	}
+	if q.maxMilePost != nil && milePost > *q.maxMilePost {
+		return false
+	}
+
+	if len(q.directions) > 0 {
+		direction := normalizeDirection(camera.CameraLocation.Direction)
+		if direction != "B" && !slices.Contains(q.directions, direction) {
+			return false
+		}
+	}
+
+	if len(q.regions) > 0 && !slices.Contains(q.regions, camera.Region) {
+		return false
+	}
+
+	if len(q.owners) > 0 && !slices.Contains(q.owners, strings.ToUpper(camera.CameraOwner.Value)) {
+		return false
+	}
+
+	return true
+}
+
+// Filter returns the cameras matching the query, keeping their order.
+func (q *Query) Filter(cameras []Camera) []Camera {
+	var matched []Camera
+	for _, camera := range cameras {
+		if q.Match(camera) {
+			matched = append(matched, camera)
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,61 @@
+package cameras
+
+import (
+	"testing"
+)
+
+func TestNormalizeRoadName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "I-5", want: "005"},
+		{name: "i 5", want: "005"},
+		{name: "005", want: "005"},
+		{name: "SR 520", want: "520"},
+		{name: "US 2", want: "002"},
+		{name: "SR 20 Spur", want: "020SPUR"},
+		{name: " Alaskan Way ", want: "ALASKAN WAY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeRoadName(tt.name); got != tt.want {
+				t.Errorf("NormalizeRoadName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryFilter(t *testing.T) {
+	camera := func(id int, road string, milePost int, direction string, region Region, active bool) Camera {
+		return Camera{
+			CameraID:       id,
+			CameraLocation: CameraLocation{RoadName: road, MilePost: milePost, Direction: direction},
+			Region:         region,
+			IsActive:       active,
+		}
+	}
+
+	cameras := []Camera{
+		camera(1, "I-5", 165, "N", RegionNorthwest, true),
+		camera(2, "005", 175, "B", RegionNorthwest, true),
+		camera(3, "I-5", 165, "S", RegionNorthwest, true),
+		camera(4, "I-5", 190, "N", RegionNorthwest, true),
+		camera(5, "I-5", 160, "N", RegionNorthwest, false),
+		camera(6, "I-405", 160, "N", RegionNorthwest, true),
+		camera(7, "I-5", 160, "N", RegionOlympic, true),
+	}
+
+	got := NewQuery().
+		Road("I-5").
+		MilePostBetween(150, 180).
+		Direction("northbound").
+		Region(RegionNorthwest).
+		Active().
+		Filter(cameras)
+
+	if ids := cameraIDs(got); !equalIDs(ids, []int{1, 2}) {
+		t.Errorf("Filter() = %v, want [1 2]", ids)
+	}
+}
@@ -0,0 +1,281 @@
+package cameras
+
+import (
+	"math"
+	"sort"
+
+	"alpineworks.io/wsdot/internal/geo"
+)
+
+const (
+	// DefaultCellSize is the grid cell size, in degrees, used by
+	// NewSpatialIndex. Roughly 5.5km north-south at Washington latitudes.
+	DefaultCellSize = 0.05
+)
+
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type cell struct {
+	row int
+	col int
+}
+
+// SpatialIndex answers location queries over a fixed set of cameras using
+// their CameraLocation coordinates. Build it once per GetCameras result and
+// reuse it for repeated queries.
+type SpatialIndex struct {
+	cameras  []Camera
+	cellSize float64
+	cells    map[cell][]int
+
+	minRow, maxRow int
+	minCol, maxCol int
+}
+
+func NewSpatialIndex(cameras []Camera) *SpatialIndex {
+	return NewSpatialIndexWithCellSize(cameras, DefaultCellSize)
+}
+
+func NewSpatialIndexWithCellSize(cameras []Camera, cellSize float64) *SpatialIndex {
+	if cellSize <= 0 {
+		cellSize = DefaultCellSize
+	}
+
+	index := &SpatialIndex{
+		cameras:  cameras,
+		cellSize: cellSize,
+		cells:    make(map[cell][]int),
+	}
+
+	first := true
+	for i, camera := range cameras {
+		location := camera.point()
+		if location.Latitude == 0 && location.Longitude == 0 {
+			continue
+		}
+
+		c := index.cellOf(location)
+		index.cells[c] = append(index.cells[c], i)
+
+		if first {
+			index.minRow, index.maxRow, index.minCol, index.maxCol = c.row, c.row, c.col, c.col
+			first = false
+			continue
+		}
+
+		index.minRow = min(index.minRow, c.row)
+		index.maxRow = max(index.maxRow, c.row)
+		index.minCol = min(index.minCol, c.col)
+		index.maxCol = max(index.maxCol, c.col)
+	}
+
+	return index
+}
+
+func (c Camera) point() geo.Point {
+	return geo.Point{Latitude: c.CameraLocation.Latitude, Longitude: c.CameraLocation.Longitude}
+}
+
+func (s *SpatialIndex) cellOf(p geo.Point) cell {
+	return cell{
+		row: int(math.Floor(p.Latitude / s.cellSize)),
+		col: int(math.Floor(p.Longitude / s.cellSize)),
+	}
+}
+
+// collect adds the indexes of the cameras in the cells overlapping the
+// bounding box.
+func (s *SpatialIndex) collect(minLat, minLon, maxLat, maxLon float64, into map[int]struct{}) {
+	low := s.cellOf(geo.Point{Latitude: minLat, Longitude: minLon})
+	high := s.cellOf(geo.Point{Latitude: maxLat, Longitude: maxLon})
+
+	for row := max(low.row, s.minRow); row <= min(high.row, s.maxRow); row++ {
+		for col := max(low.col, s.minCol); col <= min(high.col, s.maxCol); col++ {
+			for _, i := range s.cells[cell{row: row, col: col}] {
+				into[i] = struct{}{}
+			}
+		}
+	}
+}
+
+type candidate struct {
+	index    int
+	distance float64
+}
+
+func (s *SpatialIndex) sorted(candidates []candidate) []Camera {
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance == candidates[j].distance {
+			return candidates[i].index < candidates[j].index
+		}
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	cameras := make([]Camera, 0, len(candidates))
+	for _, c := range candidates {
+		cameras = append(cameras, s.cameras[c.index])
+	}
+
+	return cameras
+}
+
+// Nearest returns up to n cameras closest to p, nearest first.
+func (s *SpatialIndex) Nearest(p Point, n int) []Camera {
+	if n <= 0 || len(s.cells) == 0 {
+		return nil
+	}
+
+	origin := geo.Point(p)
+	center := s.cellOf(origin)
+
+	// skip the rings that cannot reach any indexed cell
+	start := max(0, s.minRow-center.row, center.row-s.maxRow, s.minCol-center.col, center.col-s.maxCol)
+
+	var candidates []candidate
+	visit := func(row, col int) {
+		for _, i := range s.cells[cell{row: row, col: col}] {
+			candidates = append(candidates, candidate{index: i, distance: geo.Distance(origin, s.cameras[i].point())})
+		}
+	}
+
+	for ring := start; ; ring++ {
+		top, bottom := center.row-ring, center.row+ring
+		left, right := center.col-ring, center.col+ring
+
+		for row := max(top, s.minRow); row <= min(bottom, s.maxRow); row++ {
+			if row == top || row == bottom {
+				for col := max(left, s.minCol); col <= min(right, s.maxCol); col++ {
+					visit(row, col)
+				}
+				continue
+			}
+
+			visit(row, left)
+			if right != left {
+				visit(row, right)
+			}
+		}
+
+		exhausted := center.row-ring <= s.minRow && center.row+ring >= s.maxRow &&
+			center.col-ring <= s.minCol && center.col+ring >= s.maxCol
+		if exhausted {
+			break
+		}
+
+		if len(candidates) >= n {
+			// anything outside the visited rings is at least ring cells away
+			sort.Slice(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+			farLatitude := math.Min(89, math.Abs(p.Latitude)+float64(ring+1)*s.cellSize)
+			bound := float64(ring) * s.cellSize * geo.MetersPerDegree * math.Cos(farLatitude*math.Pi/180)
+			if candidates[n-1].distance <= bound {
+				break
+			}
+		}
+	}
+
+	cameras := s.sorted(candidates)
+	if len(cameras) > n {
+		cameras = cameras[:n]
+	}
+
+	return cameras
+}
+
+// WithinRadius returns the cameras within meters of p, nearest first.
+func (s *SpatialIndex) WithinRadius(p Point, meters float64) []Camera {
+	origin := geo.Point(p)
+	latDelta, lonDelta := s.degreesAround(origin, meters)
+
+	found := make(map[int]struct{})
+	s.collect(p.Latitude-latDelta, p.Longitude-lonDelta, p.Latitude+latDelta, p.Longitude+lonDelta, found)
+
+	var candidates []candidate
+	for i := range found {
+		if d := geo.Distance(origin, s.cameras[i].point()); d <= meters {
+			candidates = append(candidates, candidate{index: i, distance: d})
+		}
+	}
+
+	return s.sorted(candidates)
+}
+
+// WithinBounds returns the cameras inside the bounding box, in the order they
+// were given to the index.
+func (s *SpatialIndex) WithinBounds(southWest, northEast Point) []Camera {
+	found := make(map[int]struct{})
+	s.collect(southWest.Latitude, southWest.Longitude, northEast.Latitude, northEast.Longitude, found)
+
+	var candidates []candidate
+	for i := range found {
+		location := s.cameras[i].point()
+		if location.Latitude >= southWest.Latitude && location.Latitude <= northEast.Latitude &&
+			location.Longitude >= southWest.Longitude && location.Longitude <= northEast.Longitude {
+			candidates = append(candidates, candidate{index: i})
+		}
+	}
+
+	return s.sorted(candidates)
+}
+
+// AlongCorridor returns the cameras within meters of the polyline, ordered by
+// how far along the polyline they are.
+func (s *SpatialIndex) AlongCorridor(polyline []Point, meters float64) []Camera {
+	if len(polyline) == 0 {
+		return nil
+	}
+	if len(polyline) == 1 {
+		return s.WithinRadius(polyline[0], meters)
+	}
+
+	found := make(map[int]struct{})
+	for i := 1; i < len(polyline); i++ {
+		a, b := polyline[i-1], polyline[i]
+		latDelta, lonDelta := s.degreesAround(geo.Point(a), meters)
+		if bLatDelta, bLonDelta := s.degreesAround(geo.Point(b), meters); bLonDelta > lonDelta {
+			latDelta, lonDelta = bLatDelta, bLonDelta
+		}
+
+		s.collect(
+			math.Min(a.Latitude, b.Latitude)-latDelta, math.Min(a.Longitude, b.Longitude)-lonDelta,
+			math.Max(a.Latitude, b.Latitude)+latDelta, math.Max(a.Longitude, b.Longitude)+lonDelta,
+			found,
+		)
+	}
+
+	var candidates []candidate
+	for i := range found {
+		location := s.cameras[i].point()
+
+		best, along, travelled := math.Inf(1), 0.0, 0.0
+		for j := 1; j < len(polyline); j++ {
+			a, b := geo.Point(polyline[j-1]), geo.Point(polyline[j])
+			length := geo.Distance(a, b)
+
+			d, t := geo.DistanceToSegment(location, a, b)
+			if d < best {
+				best, along = d, travelled+t*length
+			}
+			travelled += length
+		}
+
+		if best <= meters {
+			candidates = append(candidates, candidate{index: i, distance: along})
+		}
+	}
+
+	return s.sorted(candidates)
+}
+
+// degreesAround converts a distance around p into latitude and longitude
+// deltas that are guaranteed to cover it.
+func (s *SpatialIndex) degreesAround(p geo.Point, meters float64) (float64, float64) {
+	latDelta := meters / geo.MetersPerDegree
+
+	cosLat := math.Cos(math.Min(89, math.Abs(p.Latitude)+latDelta) * math.Pi / 180)
+	lonDelta := meters / (geo.MetersPerDegree * cosLat)
+
+	return latDelta, lonDelta
+}
@@ -0,0 +1,86 @@
+package cameras
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"alpineworks.io/wsdot/internal/geo"
+)
+
+func testCamera(id int, latitude, longitude float64) Camera {
+	return Camera{CameraID: id, CameraLocation: CameraLocation{Latitude: latitude, Longitude: longitude}}
+}
+
+func cameraIDs(cameras []Camera) []int {
+	ids := make([]int, 0, len(cameras))
+	for _, camera := range cameras {
+		ids = append(ids, camera.CameraID)
+	}
+	return ids
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSpatialIndexNearestMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	var cameras []Camera
+	for i := 0; i < 500; i++ {
+		cameras = append(cameras, testCamera(i, 45.5+r.Float64()*3.5, -124.5+r.Float64()*7.5))
+	}
+	index := NewSpatialIndex(cameras)
+
+	for q := 0; q < 50; q++ {
+		p := Point{Latitude: 45 + r.Float64()*5, Longitude: -125 + r.Float64()*9}
+
+		want := append([]Camera(nil), cameras...)
+		sort.SliceStable(want, func(i, j int) bool {
+			return geo.Distance(geo.Point(p), want[i].point()) < geo.Distance(geo.Point(p), want[j].point())
+		})
+
+		got := index.Nearest(p, 5)
+		if !equalIDs(cameraIDs(got), cameraIDs(want[:5])) {
+			t.Fatalf("Nearest(%v) = %v, want %v", p, cameraIDs(got), cameraIDs(want[:5]))
+		}
+	}
+}
+
+func TestSpatialIndexQueries(t *testing.T) {
+	cameras := []Camera{
+		testCamera(1, 47.6062, -122.3321), // Seattle
+		testCamera(2, 47.2529, -122.4443), // Tacoma
+		testCamera(3, 47.9790, -122.2021), // Everett
+		testCamera(4, 47.6588, -117.4260), // Spokane
+		testCamera(5, 0, 0),               // no location
+	}
+	index := NewSpatialIndex(cameras)
+
+	if got := cameraIDs(index.WithinRadius(Point{Latitude: 47.6062, Longitude: -122.3321}, 45000)); !equalIDs(got, []int{1, 2, 3}) {
+		t.Errorf("WithinRadius() = %v, want [1 2 3]", got)
+	}
+
+	if got := cameraIDs(index.WithinBounds(Point{Latitude: 47, Longitude: -123}, Point{Latitude: 47.7, Longitude: -122})); !equalIDs(got, []int{1, 2}) {
+		t.Errorf("WithinBounds() = %v, want [1 2]", got)
+	}
+
+	// I-5 from Everett to Tacoma
+	corridor := []Point{
+		{Latitude: 47.98, Longitude: -122.20},
+		{Latitude: 47.60, Longitude: -122.33},
+		{Latitude: 47.25, Longitude: -122.44},
+	}
+	if got := cameraIDs(index.AlongCorridor(corridor, 2000)); !equalIDs(got, []int{3, 1, 2}) {
+		t.Errorf("AlongCorridor() = %v, want [3 1 2]", got)
+	}
+}
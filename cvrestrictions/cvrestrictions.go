@@ -0,0 +1,115 @@
+package cvrestrictions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"alpineworks.io/wsdot"
+)
+
+const (
+	getCommercialVehicleRestrictionsAsJsonURL = "http://www.wsdot.wa.gov/Traffic/api/CVRestrictions/CVRestrictionsREST.svc/GetCommercialVehicleRestrictionsAsJson"
+)
+
+type CVRestrictionsClient struct {
+	wsdot *wsdot.WSDOTClient
+}
+
+func NewCVRestrictionsClient(wsdotClient *wsdot.WSDOTClient) (*CVRestrictionsClient, error) {
+	if wsdotClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return &CVRestrictionsClient{
+		wsdot: wsdotClient,
+	}, nil
+}
+
+type RestrictionType int
+
+const (
+	RestrictionTypeBridge RestrictionType = 0
+	RestrictionTypeRoad   RestrictionType = 1
+)
+
+func (r RestrictionType) String() string {
+	switch r {
+	case RestrictionTypeBridge:
+		return "bridge"
+	case RestrictionTypeRoad:
+		return "road"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(r))
+	}
+}
+
+type RoadwayLocation struct {
+	Description *string `json:"Description"`
+	Direction   string  `json:"Direction"`
+	Latitude    float64 `json:"Latitude"`
+	Longitude   float64 `json:"Longitude"`
+	MilePost    float64 `json:"MilePost"`
+	RoadName    string  `json:"RoadName"`
+}
+
+type CommercialVehicleRestriction struct {
+	BLMaxAxle                         int             `json:"BLMaxAxle"`
+	BridgeName                        string          `json:"BridgeName"`
+	BridgeNumber                      string          `json:"BridgeNumber"`
+	CL8MaxAxle                        int             `json:"CL8MaxAxle"`
+	DateEffective                     string          `json:"DateEffective"`
+	DateExpires                       string          `json:"DateExpires"`
+	DatePosted                        string          `json:"DatePosted"`
+	EndRoadwayLocation                RoadwayLocation `json:"EndRoadwayLocation"`
+	IsDetourAvailable                 bool            `json:"IsDetourAvailable"`
+	IsExceptionsAllowed               bool            `json:"IsExceptionsAllowed"`
+	IsPermanentRestriction            bool            `json:"IsPermanentRestriction"`
+	IsWarning                         bool            `json:"IsWarning"`
+	Latitude                          float64         `json:"Latitude"`
+	LocationDescription               string          `json:"LocationDescription"`
+	LocationName                      string          `json:"LocationName"`
+	Longitude                         float64         `json:"Longitude"`
+	MaximumGrossVehicleWeightInPounds int             `json:"MaximumGrossVehicleWeightInPounds"`
+	RestrictionComment                string          `json:"RestrictionComment"`
+	RestrictionHeightInInches         int             `json:"RestrictionHeightInInches"`
+	RestrictionLengthInInches         int             `json:"RestrictionLengthInInches"`
+	RestrictionType                   RestrictionType `json:"RestrictionType"`
+	RestrictionWeightInPounds         int             `json:"RestrictionWeightInPounds"`
+	RestrictionWidthInInches          int             `json:"RestrictionWidthInInches"`
+	SAMaxAxle                         int             `json:"SAMaxAxle"`
+	StartRoadwayLocation              RoadwayLocation `json:"StartRoadwayLocation"`
+	State                             string          `json:"State"`
+	StateRouteID                      string          `json:"StateRouteID"`
+	TDMaxAxle                         int             `json:"TDMaxAxle"`
+	VehicleType                       string          `json:"VehicleType"`
+}
+
+func (c *CVRestrictionsClient) GetRestrictions() ([]CommercialVehicleRestriction, error) {
+	req, err := http.NewRequest(http.MethodGet, getCommercialVehicleRestrictionsAsJsonURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	q := req.URL.Query()
+	q.Add(wsdot.ParamCamerasAccessCodeKey, c.wsdot.ApiKey)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.wsdot.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var restrictions []CommercialVehicleRestriction
+	if err := json.NewDecoder(resp.Body).Decode(&restrictions); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return restrictions, nil
+}
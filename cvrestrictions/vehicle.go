@@ -0,0 +1,60 @@
+package cvrestrictions
+
+import (
+	"strings"
+)
+
+// VehicleProfile describes the dimensions of a commercial vehicle. Zero values
+// are treated as unknown and never trigger a restriction.
+type VehicleProfile struct {
+	GrossWeightInPounds int
+	HeightInInches      int
+	WidthInInches       int
+	LengthInInches      int
+}
+
+// Affects reports whether the vehicle exceeds any of the limits posted by the
+// restriction. Limits reported as zero by WSDOT are not enforced.
+func (r CommercialVehicleRestriction) Affects(profile VehicleProfile) bool {
+	exceeds := func(limit, value int) bool {
+		return limit > 0 && value > limit
+	}
+
+	return exceeds(r.RestrictionWeightInPounds, profile.GrossWeightInPounds) ||
+		exceeds(r.MaximumGrossVehicleWeightInPounds, profile.GrossWeightInPounds) ||
+		exceeds(r.RestrictionHeightInInches, profile.HeightInInches) ||
+		exceeds(r.RestrictionWidthInInches, profile.WidthInInches) ||
+		exceeds(r.RestrictionLengthInInches, profile.LengthInInches)
+}
+
+// RestrictionsForVehicle returns the restrictions on stateRouteID that affect
+// the given vehicle, ordered as they were returned by the API. An empty
+// stateRouteID matches every route.
+func RestrictionsForVehicle(restrictions []CommercialVehicleRestriction, stateRouteID string, profile VehicleProfile) []CommercialVehicleRestriction {
+	var affecting []CommercialVehicleRestriction
+	for _, restriction := range restrictions {
+		if stateRouteID != "" && !sameStateRoute(restriction.StateRouteID, stateRouteID) {
+			continue
+		}
+
+		if restriction.Affects(profile) {
+			affecting = append(affecting, restriction)
+		}
+	}
+
+	return affecting
+}
+
+// sameStateRoute compares route identifiers such as "005" and "5".
+func sameStateRoute(a, b string) bool {
+	normalize := func(s string) string {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		trimmed := strings.TrimLeft(s, "0")
+		if trimmed == "" && s != "" {
+			return "0"
+		}
+		return trimmed
+	}
+
+	return normalize(a) == normalize(b)
+}
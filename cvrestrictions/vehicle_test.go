@@ -0,0 +1,59 @@
+package cvrestrictions
+
+import (
+	"testing"
+)
+
+func TestRestrictionsForVehicle(t *testing.T) {
+	restrictions := []CommercialVehicleRestriction{
+		{BridgeNumber: "1", StateRouteID: "005", RestrictionHeightInInches: 162},
+		{BridgeNumber: "2", StateRouteID: "005", RestrictionWeightInPounds: 80000},
+		{BridgeNumber: "3", StateRouteID: "520", RestrictionWidthInInches: 96},
+		{BridgeNumber: "4", StateRouteID: "005"},
+	}
+
+	tests := []struct {
+		name         string
+		stateRouteID string
+		profile      VehicleProfile
+		want         []string
+	}{
+		{
+			name:         "Too tall on route 5",
+			stateRouteID: "5",
+			profile:      VehicleProfile{HeightInInches: 168},
+			want:         []string{"1"},
+		},
+		{
+			name:         "Too heavy on route 005",
+			stateRouteID: "005",
+			profile:      VehicleProfile{GrossWeightInPounds: 90000, HeightInInches: 150},
+			want:         []string{"2"},
+		},
+		{
+			name:    "Too wide on any route",
+			profile: VehicleProfile{WidthInInches: 102},
+			want:    []string{"3"},
+		},
+		{
+			name:         "Within every limit",
+			stateRouteID: "005",
+			profile:      VehicleProfile{GrossWeightInPounds: 40000, HeightInInches: 150, WidthInInches: 96},
+			want:         nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RestrictionsForVehicle(restrictions, tt.stateRouteID, tt.profile)
+			if len(got) != len(tt.want) {
+				t.Fatalf("RestrictionsForVehicle() returned %d restrictions, want %d", len(got), len(tt.want))
+			}
+			for i, restriction := range got {
+				if restriction.BridgeNumber != tt.want[i] {
+					t.Errorf("RestrictionsForVehicle()[%d] = %s, want %s", i, restriction.BridgeNumber, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/ferries"
+	"alpineworks.io/wsdot/ferries/ais"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		panic("API_KEY environment variable is required")
+	}
+
+	// Create a new WSDOT client
+	wsdotClient, err := wsdot.NewWSDOTClient(
+		wsdot.WithAPIKey(apiKey),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a new Ferries client
+	ferriesClient, err := ferries.NewFerriesClient(wsdotClient)
+	if err != nil {
+		panic(err)
+	}
+
+	// Watch vessel locations
+	watcher, err := ferries.NewVesselWatcher(ferriesClient)
+	if err != nil {
+		panic(err)
+	}
+
+	// Serve AIS on TCP port 10110; add it to OpenCPN as a TCP network connection
+	broadcaster := ais.NewBroadcaster()
+	defer broadcaster.Close()
+
+	addr, err := broadcaster.ListenTCP(":10110")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("serving AIS on %s\n", addr)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	go func() {
+		_ = watcher.Run(ctx)
+	}()
+
+	if err := broadcaster.Run(ctx, watcher.Updates()); err != nil && ctx.Err() == nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/bordercrossings"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		panic("API_KEY environment variable is required")
+	}
+
+	// Create a new WSDOT client
+	wsdotClient, err := wsdot.NewWSDOTClient(
+		wsdot.WithAPIKey(apiKey),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a new Border Crossings client
+	borderCrossingsClient, err := bordercrossings.NewBorderCrossingsClient(wsdotClient)
+	if err != nil {
+		panic(err)
+	}
+
+	// Get the border crossings
+	crossings, err := borderCrossingsClient.GetBorderCrossings()
+	if err != nil {
+		panic(err)
+	}
+
+	// Rank the general purpose lanes by current wait
+	for _, crossing := range bordercrossings.RankByWait(crossings, bordercrossings.LaneTypeGeneral) {
+		fmt.Printf("%s: %d minutes\n", crossing.CrossingName.PortOfEntry(), crossing.WaitTime)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/bridgeclearances"
+	"alpineworks.io/wsdot/cvrestrictions"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		panic("API_KEY environment variable is required")
+	}
+
+	// Create a new WSDOT client
+	wsdotClient, err := wsdot.NewWSDOTClient(
+		wsdot.WithAPIKey(apiKey),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a new Commercial Vehicle Restrictions client
+	cvRestrictionsClient, err := cvrestrictions.NewCVRestrictionsClient(wsdotClient)
+	if err != nil {
+		panic(err)
+	}
+
+	// Get the restrictions affecting a 13'6" tall, 90,000 lb truck on I-5
+	restrictions, err := cvRestrictionsClient.GetRestrictions()
+	if err != nil {
+		panic(err)
+	}
+
+	profile := cvrestrictions.VehicleProfile{
+		GrossWeightInPounds: 90000,
+		HeightInInches:      162,
+	}
+
+	for _, restriction := range cvrestrictions.RestrictionsForVehicle(restrictions, "005", profile) {
+		fmt.Printf("%s (%s) at milepost %.2f\n", restriction.LocationName, restriction.RestrictionType, restriction.StartRoadwayLocation.MilePost)
+	}
+
+	// Create a new Bridge Clearances client
+	bridgeClearancesClient, err := bridgeclearances.NewBridgeClearancesClient(wsdotClient)
+	if err != nil {
+		panic(err)
+	}
+
+	// Get the low clearances on I-5
+	clearances, err := bridgeClearancesClient.GetClearancesByRoute("005")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, clearance := range bridgeclearances.ClearancesBelowHeight(clearances, 162) {
+		fmt.Printf("%s at milepost %.2f: %s\n", clearance.CrossingDescription, clearance.SRMP, clearance.VerticalClearanceMinimumFeetInch)
+	}
+}
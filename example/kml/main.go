@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/cameras"
+	"alpineworks.io/wsdot/ferries"
+	"alpineworks.io/wsdot/kml"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		panic("API_KEY environment variable is required")
+	}
+
+	// Create a new WSDOT client
+	wsdotClient, err := wsdot.NewWSDOTClient(
+		wsdot.WithAPIKey(apiKey),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	camerasClient, err := cameras.NewCamerasClient(wsdotClient)
+	if err != nil {
+		panic(err)
+	}
+
+	ferriesClient, err := ferries.NewFerriesClient(wsdotClient)
+	if err != nil {
+		panic(err)
+	}
+
+	camerasHandler, err := kml.NewCamerasHandler(camerasClient)
+	if err != nil {
+		panic(err)
+	}
+
+	vesselsHandler, err := kml.NewVesselsHandler(ferriesClient)
+	if err != nil {
+		panic(err)
+	}
+
+	terminalsHandler, err := kml.NewTerminalsHandler(ferriesClient)
+	if err != nil {
+		panic(err)
+	}
+
+	// Open http://localhost:8080/wsdot.kml in Google Earth to subscribe to
+	// the layers below
+	root := "http://localhost:8080"
+	networkLinkHandler := kml.NewNetworkLinkHandler("WSDOT", []kml.NetworkLink{
+		{Name: "Cameras", Href: root + "/cameras.kml", RefreshInterval: 5 * time.Minute},
+		{Name: "Vessels", Href: root + "/vessels.kml", RefreshInterval: 10 * time.Second},
+		{Name: "Terminals", Href: root + "/terminals.kml"},
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/wsdot.kml", networkLinkHandler)
+	mux.Handle("/cameras.kml", camerasHandler)
+	mux.Handle("/vessels.kml", vesselsHandler)
+	mux.Handle("/terminals.kml", terminalsHandler)
+
+	fmt.Println("serving KML on " + root + "/wsdot.kml")
+	if err := http.ListenAndServe(":8080", mux); err != nil {
+		panic(err)
+	}
+}
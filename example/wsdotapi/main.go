@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/wsdotapi"
+)
+
+func main() {
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		panic("API_KEY environment variable is required")
+	}
+
+	// Create a single client for every WSDOT API family
+	client, err := wsdotapi.NewClient(
+		wsdot.WithAPIKey(apiKey),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	// Get the cameras
+	cameras, err := client.Cameras().GetCameras()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%d cameras\n", len(cameras))
+
+	// Get the vessel locations
+	vesselLocations, err := client.Ferries().GetVesselLocations()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%d vessels\n", len(vesselLocations))
+}
@@ -0,0 +1,260 @@
+package ais
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+const (
+	// DefaultStaticInterval matches the six minute reporting interval AIS
+	// uses for static and voyage related data.
+	DefaultStaticInterval = 6 * time.Minute
+
+	writeTimeout = 5 * time.Second
+	// clientQueueSize is how many broadcasts a TCP client may fall behind by
+	// before it is disconnected
+	clientQueueSize = 64
+)
+
+// client is a connected TCP client. Broadcasts are queued and written by the
+// client's own goroutine, so a slow client does not hold up the others.
+type client struct {
+	conn  net.Conn
+	queue chan []byte
+}
+
+// Broadcaster sends AIVDM sentences to TCP clients and UDP targets, the two
+// transports chart plotters and OpenCPN accept as network data sources.
+type Broadcaster struct {
+	encoder        *Encoder
+	staticInterval time.Duration
+
+	mu         sync.Mutex
+	listeners  []net.Listener
+	clients    map[*client]struct{}
+	udpTargets []*net.UDPConn
+	lastStatic map[int]time.Time
+}
+
+type BroadcasterOption func(*Broadcaster)
+
+func NewBroadcaster(options ...BroadcasterOption) *Broadcaster {
+	broadcaster := &Broadcaster{
+		encoder:        NewEncoder(),
+		staticInterval: DefaultStaticInterval,
+		clients:        make(map[*client]struct{}),
+		lastStatic:     make(map[int]time.Time),
+	}
+
+	for _, option := range options {
+		option(broadcaster)
+	}
+
+	return broadcaster
+}
+
+// WithStaticInterval sets how often each vessel's type 5 static and voyage
+// message is repeated.
+func WithStaticInterval(staticInterval time.Duration) BroadcasterOption {
+	return func(b *Broadcaster) {
+		b.staticInterval = staticInterval
+	}
+}
+
+// ListenTCP accepts TCP clients on addr, e.g. ":10110", and streams every
+// broadcast sentence to them until Close is called.
+func (b *Broadcaster) ListenTCP(addr string) (net.Addr, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("error listening: %v", err)
+	}
+
+	b.mu.Lock()
+	b.listeners = append(b.listeners, listener)
+	b.mu.Unlock()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					slog.Warn("error accepting ais client", "error", err)
+				}
+				return
+			}
+
+			b.addClient(conn)
+		}
+	}()
+
+	return listener.Addr(), nil
+}
+
+func (b *Broadcaster) addClient(conn net.Conn) {
+	c := &client{
+		conn:  conn,
+		queue: make(chan []byte, clientQueueSize),
+	}
+
+	b.mu.Lock()
+	b.clients[c] = struct{}{}
+	b.mu.Unlock()
+
+	go b.serve(c)
+}
+
+// serve writes the client's queued broadcasts until it is dropped or a write
+// fails.
+func (b *Broadcaster) serve(c *client) {
+	for data := range c.queue {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := c.conn.Write(data); err != nil {
+			b.mu.Lock()
+			b.dropClient(c)
+			b.mu.Unlock()
+			return
+		}
+	}
+}
+
+// dropClient disconnects the client. b.mu must be held.
+func (b *Broadcaster) dropClient(c *client) {
+	if _, ok := b.clients[c]; !ok {
+		return
+	}
+
+	delete(b.clients, c)
+	close(c.queue)
+	c.conn.Close()
+}
+
+// AddUDPTarget sends every broadcast sentence to addr, e.g. "127.0.0.1:10110"
+// or a broadcast address.
+func (b *Broadcaster) AddUDPTarget(addr string) error {
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return fmt.Errorf("error resolving address: %v", err)
+	}
+
+	conn, err := net.DialUDP("udp", nil, udpAddr)
+	if err != nil {
+		return fmt.Errorf("error dialing: %v", err)
+	}
+
+	b.mu.Lock()
+	b.udpTargets = append(b.udpTargets, conn)
+	b.mu.Unlock()
+
+	return nil
+}
+
+// Broadcast writes the sentences to every client and target. TCP clients
+// that fall too far behind or fail to accept a write are disconnected.
+func (b *Broadcaster) Broadcast(sentences []string) {
+	if len(sentences) == 0 {
+		return
+	}
+
+	data := []byte(strings.Join(sentences, "\r\n") + "\r\n")
+
+	b.mu.Lock()
+	for c := range b.clients {
+		select {
+		case c.queue <- data:
+		default:
+			slog.Warn("ais client is not keeping up, disconnecting", "client", c.conn.RemoteAddr())
+			b.dropClient(c)
+		}
+	}
+	udpTargets := slices.Clone(b.udpTargets)
+	b.mu.Unlock()
+
+	for _, conn := range udpTargets {
+		// one datagram per sentence, as most receivers expect
+		for _, sentence := range sentences {
+			if _, err := conn.Write([]byte(sentence + "\r\n")); err != nil {
+				slog.Warn("error sending ais datagram", "error", err)
+			}
+		}
+	}
+}
+
+// BroadcastLocation sends the vessel's position report, preceded by its
+// static and voyage data when that is due.
+func (b *Broadcaster) BroadcastLocation(location ferries.VesselLocation) error {
+	var sentences []string
+
+	b.mu.Lock()
+	staticDue := time.Since(b.lastStatic[location.VesselID]) >= b.staticInterval
+	b.mu.Unlock()
+
+	if staticDue {
+		static, err := b.encoder.EncodeStaticVoyageData(location)
+		if err != nil {
+			return err
+		}
+		sentences = append(sentences, static...)
+
+		b.mu.Lock()
+		b.lastStatic[location.VesselID] = time.Now()
+		b.mu.Unlock()
+	}
+
+	position, err := b.encoder.EncodePositionReport(location)
+	if err != nil {
+		return err
+	}
+	sentences = append(sentences, position...)
+
+	b.Broadcast(sentences)
+
+	return nil
+}
+
+// Run broadcasts every location received from updates, typically a
+// VesselWatcher's Updates channel, until ctx is cancelled or updates is
+// closed. Vessels without an MMSI are skipped.
+func (b *Broadcaster) Run(ctx context.Context, updates <-chan ferries.VesselLocation) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case location, ok := <-updates:
+			if !ok {
+				return nil
+			}
+
+			if err := b.BroadcastLocation(location); err != nil && !errors.Is(err, ErrNoMMSI) {
+				return err
+			}
+		}
+	}
+}
+
+func (b *Broadcaster) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var errs []error
+	for _, listener := range b.listeners {
+		errs = append(errs, listener.Close())
+	}
+	for c := range b.clients {
+		b.dropClient(c)
+	}
+	for _, conn := range b.udpTargets {
+		errs = append(errs, conn.Close())
+	}
+	b.listeners, b.udpTargets = nil, nil
+
+	return errors.Join(errs...)
+}
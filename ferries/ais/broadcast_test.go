@@ -0,0 +1,197 @@
+package ais
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+var testSentences = []string{
+	"!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A",
+	"!AIVDM,1,1,,B,177KQJ5000G?tO`K>RA1wUbN0TKH,0*5C",
+}
+
+func clientCount(b *Broadcaster) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.clients)
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func readLines(t *testing.T, reader *bufio.Reader, n int) []string {
+	t.Helper()
+
+	var lines []string
+	for i := 0; i < n; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("error reading sentence: %v", err)
+		}
+		lines = append(lines, strings.TrimRight(line, "\r\n"))
+	}
+
+	return lines
+}
+
+func TestBroadcasterTCP(t *testing.T) {
+	broadcaster := NewBroadcaster()
+	defer broadcaster.Close()
+
+	addr, err := broadcaster.ListenTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenTCP() error = %v", err)
+	}
+
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	waitFor(t, func() bool { return clientCount(broadcaster) == 1 })
+
+	broadcaster.Broadcast(testSentences)
+
+	got := readLines(t, bufio.NewReader(conn), len(testSentences))
+	for i := range testSentences {
+		if got[i] != testSentences[i] {
+			t.Errorf("sentence %d = %q, want %q", i, got[i], testSentences[i])
+		}
+	}
+
+	if err := broadcaster.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if clientCount(broadcaster) != 0 {
+		t.Errorf("Close() left %d clients connected", clientCount(broadcaster))
+	}
+}
+
+func TestBroadcasterSlowClient(t *testing.T) {
+	broadcaster := NewBroadcaster()
+	defer broadcaster.Close()
+
+	// net.Pipe writes block until read, so the slow client never accepts a
+	// broadcast
+	slow, slowPeer := net.Pipe()
+	defer slowPeer.Close()
+	fast, fastPeer := net.Pipe()
+	defer fastPeer.Close()
+
+	broadcaster.addClient(slow)
+	broadcaster.addClient(fast)
+
+	received := make(chan string, clientQueueSize+2)
+	go func() {
+		reader := bufio.NewReader(fastPeer)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			received <- line
+		}
+	}()
+
+	// the slow client is dropped once its queue fills, while the fast client
+	// keeps receiving every broadcast
+	for i := 0; i < clientQueueSize+2; i++ {
+		broadcaster.Broadcast(testSentences[:1])
+
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("fast client received %d of %d broadcasts", i, clientQueueSize+2)
+		}
+	}
+
+	if clientCount(broadcaster) != 1 {
+		t.Errorf("%d clients connected, want only the fast client", clientCount(broadcaster))
+	}
+}
+
+func TestBroadcasterUDP(t *testing.T) {
+	receiver, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.Close()
+	_ = receiver.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	broadcaster := NewBroadcaster()
+	defer broadcaster.Close()
+
+	if err := broadcaster.AddUDPTarget(receiver.LocalAddr().String()); err != nil {
+		t.Fatalf("AddUDPTarget() error = %v", err)
+	}
+
+	broadcaster.Broadcast(testSentences)
+
+	// one datagram per sentence
+	buf := make([]byte, 1024)
+	for i, want := range testSentences {
+		n, _, err := receiver.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("error reading datagram %d: %v", i, err)
+		}
+		if got := string(buf[:n]); got != want+"\r\n" {
+			t.Errorf("datagram %d = %q, want %q", i, got, want+"\r\n")
+		}
+	}
+}
+
+func TestBroadcastLocationStaticInterval(t *testing.T) {
+	receiver, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.Close()
+	_ = receiver.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	broadcaster := NewBroadcaster()
+	defer broadcaster.Close()
+
+	if err := broadcaster.AddUDPTarget(receiver.LocalAddr().String()); err != nil {
+		t.Fatal(err)
+	}
+
+	location := ferries.VesselLocation{VesselID: 1, VesselName: "Tacoma", Mmsi: 366772760, Latitude: 47.6, Longitude: -122.4}
+	for i := 0; i < 2; i++ {
+		if err := broadcaster.BroadcastLocation(location); err != nil {
+			t.Fatalf("BroadcastLocation() error = %v", err)
+		}
+	}
+
+	// the first broadcast carries the two sentence type 5 message, the second
+	// only the single sentence position report
+	var counts []string
+	buf := make([]byte, 1024)
+	for i := 0; i < 4; i++ {
+		n, _, err := receiver.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("error reading datagram %d: %v", i, err)
+		}
+		counts = append(counts, strings.Split(string(buf[:n]), ",")[1])
+	}
+
+	if got := strings.Join(counts, ""); got != "2211" {
+		t.Errorf("sentence counts = %s, want 2211", got)
+	}
+}
@@ -0,0 +1,269 @@
+// Package ais encodes vessel locations as AIS AIVDM NMEA sentences so WSF
+// vessels can be shown on marine chart plotters and tools such as OpenCPN.
+package ais
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+const (
+	// maxPayloadChars keeps each sentence within the 82 character NMEA limit
+	maxPayloadChars = 60
+	channel         = "A"
+
+	shipTypePassenger = 60
+	epfdGPS           = 1
+
+	navigationStatusUnderWay = 0
+	navigationStatusMoored   = 5
+	navigationStatusUnknown  = 15
+
+	rateOfTurnUnavailable  = -128
+	speedUnavailable       = 1023
+	courseUnavailable      = 3600
+	headingUnavailable     = 511
+	timestampUnavailable   = 60
+	longitudeUnavailable   = 181
+	latitudeUnavailable    = 91
+	etaMonthUnavailable    = 0
+	etaDayUnavailable      = 0
+	etaHourUnavailable     = 24
+	etaMinuteUnavailable   = 60
+	sixBitTextPadCharacter = '@'
+)
+
+var (
+	ErrNoMMSI = errors.New("vessel has no mmsi")
+)
+
+// Encoder turns vessel locations into AIVDM sentences. Multi-sentence
+// messages are tagged with a sequential message id, so an Encoder should be
+// shared by everything writing to the same output.
+type Encoder struct {
+	mu        sync.Mutex
+	messageID int
+}
+
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+type bitWriter struct {
+	bits []byte
+}
+
+func (w *bitWriter) uint(value uint64, width int) {
+	for i := width - 1; i >= 0; i-- {
+		w.bits = append(w.bits, byte(value>>uint(i))&1)
+	}
+}
+
+func (w *bitWriter) int(value int64, width int) {
+	w.uint(uint64(value)&(1<<uint(width)-1), width)
+}
+
+func (w *bitWriter) text(value string, chars int) {
+	value = strings.ToUpper(value)
+	for i := 0; i < chars; i++ {
+		c := byte(sixBitTextPadCharacter)
+		if i < len(value) {
+			c = value[i]
+		}
+
+		switch {
+		case c >= 64 && c <= 95:
+			w.uint(uint64(c-64), 6)
+		case c >= 32 && c <= 63:
+			w.uint(uint64(c), 6)
+		default:
+			w.uint(uint64('?'), 6)
+		}
+	}
+}
+
+// armor packs the bits into the six bit ASCII payload, returning the payload
+// and the number of fill bits added to the final character.
+func (w *bitWriter) armor() (string, int) {
+	fill := (6 - len(w.bits)%6) % 6
+	bits := append(append([]byte(nil), w.bits...), make([]byte, fill)...)
+
+	var payload strings.Builder
+	for i := 0; i < len(bits); i += 6 {
+		var value byte
+		for _, bit := range bits[i : i+6] {
+			value = value<<1 | bit
+		}
+
+		c := value + 48
+		if c > 87 {
+			c += 8
+		}
+		payload.WriteByte(c)
+	}
+
+	return payload.String(), fill
+}
+
+func checksum(sentence string) byte {
+	var sum byte
+	for i := 0; i < len(sentence); i++ {
+		sum ^= sentence[i]
+	}
+
+	return sum
+}
+
+func (e *Encoder) sentences(w *bitWriter) []string {
+	payload, fill := w.armor()
+
+	count := (len(payload) + maxPayloadChars - 1) / maxPayloadChars
+	messageID := ""
+	if count > 1 {
+		e.mu.Lock()
+		messageID = fmt.Sprint(e.messageID)
+		e.messageID = (e.messageID + 1) % 10
+		e.mu.Unlock()
+	}
+
+	sentences := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		start, end := i*maxPayloadChars, min((i+1)*maxPayloadChars, len(payload))
+
+		fragmentFill := 0
+		if i == count-1 {
+			fragmentFill = fill
+		}
+
+		body := fmt.Sprintf("AIVDM,%d,%d,%s,%s,%s,%d", count, i+1, messageID, channel, payload[start:end], fragmentFill)
+		sentences = append(sentences, fmt.Sprintf("!%s*%02X", body, checksum(body)))
+	}
+
+	return sentences
+}
+
+func navigationStatus(location ferries.VesselLocation) uint64 {
+	switch {
+	case location.AtDock:
+		return navigationStatusMoored
+	case location.InService || location.Speed > 0:
+		return navigationStatusUnderWay
+	default:
+		return navigationStatusUnknown
+	}
+}
+
+func observedAt(location ferries.VesselLocation) *time.Time {
+	if location.TimeStamp == "" {
+		return nil
+	}
+
+	t, err := ferries.ParseWSDOTTime(location.TimeStamp)
+	if err != nil {
+		return nil
+	}
+
+	return t
+}
+
+// EncodePositionReport encodes the vessel's position, speed and heading as an
+// AIS message type 1 position report.
+func (e *Encoder) EncodePositionReport(location ferries.VesselLocation) ([]string, error) {
+	if location.Mmsi == 0 {
+		return nil, ErrNoMMSI
+	}
+
+	speed := uint64(speedUnavailable)
+	if location.Speed >= 0 {
+		speed = uint64(math.Min(math.Round(location.Speed*10), speedUnavailable-1))
+	}
+
+	course, heading := uint64(courseUnavailable), uint64(headingUnavailable)
+	if location.Heading >= 0 && location.Heading < 360 {
+		course, heading = uint64(location.Heading*10), uint64(location.Heading)
+	}
+
+	longitude, latitude := int64(longitudeUnavailable*600000), int64(latitudeUnavailable*600000)
+	if location.Latitude != 0 || location.Longitude != 0 {
+		longitude = int64(math.Round(location.Longitude * 600000))
+		latitude = int64(math.Round(location.Latitude * 600000))
+	}
+
+	second := uint64(timestampUnavailable)
+	if t := observedAt(location); t != nil {
+		second = uint64(t.UTC().Second())
+	}
+
+	w := &bitWriter{}
+	w.uint(1, 6)                          // message type
+	w.uint(0, 2)                          // repeat indicator
+	w.uint(uint64(location.Mmsi), 30)     // mmsi
+	w.uint(navigationStatus(location), 4) // navigation status
+	w.int(rateOfTurnUnavailable, 8)       // rate of turn
+	w.uint(speed, 10)                     // speed over ground
+	w.uint(0, 1)                          // position accuracy
+	w.int(longitude, 28)                  // longitude
+	w.int(latitude, 27)                   // latitude
+	w.uint(course, 12)                    // course over ground
+	w.uint(heading, 9)                    // true heading
+	w.uint(second, 6)                     // time stamp
+	w.uint(0, 2)                          // maneuver indicator
+	w.uint(0, 3)                          // spare
+	w.uint(0, 1)                          // raim
+	w.uint(0, 19)                         // radio status
+
+	return e.sentences(w), nil
+}
+
+// EncodeStaticVoyageData encodes the vessel's name, type, destination and ETA
+// as an AIS message type 5. WSDOT does not publish call signs, IMO numbers or
+// dimensions, so those fields are reported as unavailable.
+func (e *Encoder) EncodeStaticVoyageData(location ferries.VesselLocation) ([]string, error) {
+	if location.Mmsi == 0 {
+		return nil, ErrNoMMSI
+	}
+
+	month, day, hour, minute := uint64(etaMonthUnavailable), uint64(etaDayUnavailable), uint64(etaHourUnavailable), uint64(etaMinuteUnavailable)
+	if location.Eta != "" {
+		if eta, err := ferries.ParseWSDOTTime(location.Eta); err == nil {
+			utc := eta.UTC()
+			month, day, hour, minute = uint64(utc.Month()), uint64(utc.Day()), uint64(utc.Hour()), uint64(utc.Minute())
+		}
+	}
+
+	destination := location.ArrivingTerminalName
+	if destination == "" {
+		destination = location.ArrivingTerminalAbbrev
+	}
+
+	w := &bitWriter{}
+	w.uint(5, 6)                      // message type
+	w.uint(0, 2)                      // repeat indicator
+	w.uint(uint64(location.Mmsi), 30) // mmsi
+	w.uint(0, 2)                      // ais version
+	w.uint(0, 30)                     // imo number
+	w.text("", 7)                     // call sign
+	w.text(location.VesselName, 20)   // vessel name
+	w.uint(shipTypePassenger, 8)      // ship type
+	w.uint(0, 9)                      // dimension to bow
+	w.uint(0, 9)                      // dimension to stern
+	w.uint(0, 6)                      // dimension to port
+	w.uint(0, 6)                      // dimension to starboard
+	w.uint(epfdGPS, 4)                // position fix type
+	w.uint(month, 4)                  // eta month
+	w.uint(day, 5)                    // eta day
+	w.uint(hour, 5)                   // eta hour
+	w.uint(minute, 6)                 // eta minute
+	w.uint(0, 8)                      // draught
+	w.text(destination, 20)           // destination
+	w.uint(0, 1)                      // dte
+	w.uint(0, 1)                      // spare
+
+	return e.sentences(w), nil
+}
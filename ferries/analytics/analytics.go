@@ -0,0 +1,219 @@
+// Package analytics measures ferry on-time performance from observed
+// departures and the published schedule.
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+const (
+	// DefaultOnTimeThreshold matches the WSF definition of an on-time
+	// departure: leaving within ten minutes of the scheduled time.
+	DefaultOnTimeThreshold = 10 * time.Minute
+
+	// matchTolerance is how far apart an observed ScheduledDeparture and a
+	// schedule entry may be and still refer to the same sailing.
+	matchTolerance = time.Minute
+)
+
+type Departure struct {
+	Route                 string     `json:"route"`
+	VesselID              int        `json:"vesselID"`
+	VesselName            string     `json:"vesselName"`
+	DepartingTerminalID   int        `json:"departingTerminalID"`
+	DepartingTerminalName string     `json:"departingTerminalName"`
+	ArrivingTerminalID    int        `json:"arrivingTerminalID"`
+	ArrivingTerminalName  string     `json:"arrivingTerminalName"`
+	Scheduled             time.Time  `json:"scheduled"`
+	Actual                *time.Time `json:"actual,omitempty"`
+	Cancelled             bool       `json:"cancelled"`
+}
+
+// Delay returns how late the sailing left. Early departures count as zero.
+func (d Departure) Delay() time.Duration {
+	if d.Actual == nil {
+		return 0
+	}
+
+	return max(0, d.Actual.Sub(d.Scheduled))
+}
+
+// DepartureFromEvent converts a VesselEventDeparted into a Departure. It
+// returns false for other events and for departures without a scheduled time.
+func DepartureFromEvent(event ferries.VesselEvent) (Departure, bool) {
+	if event.Type != ferries.VesselEventDeparted || event.ScheduledDeparture == nil {
+		return Departure{}, false
+	}
+
+	actual := event.Time
+	if event.LeftDock != nil {
+		actual = *event.LeftDock
+	}
+
+	route := ""
+	if len(event.Routes) > 0 {
+		route = event.Routes[0]
+	}
+
+	return Departure{
+		Route:                 route,
+		VesselID:              event.VesselID,
+		VesselName:            event.VesselName,
+		DepartingTerminalID:   event.DepartingTerminalID,
+		DepartingTerminalName: event.DepartingTerminalName,
+		ArrivingTerminalID:    event.ArrivingTerminalID,
+		ArrivingTerminalName:  event.ArrivingTerminalName,
+		Scheduled:             *event.ScheduledDeparture,
+		Actual:                &actual,
+	}, true
+}
+
+// Cancellations returns a cancelled Departure for every sailing in the
+// schedule that should have left more than grace before asOf but has no
+// matching observed departure.
+func Cancellations(route string, schedule ferries.Schedule, observed []Departure, asOf time.Time, grace time.Duration) []Departure {
+	var cancelled []Departure
+	for _, combo := range schedule.TerminalCombos {
+		for _, sailing := range combo.Times {
+			if sailing.DepartingTime == nil || sailing.DepartingTime.After(asOf.Add(-grace)) {
+				continue
+			}
+
+			if matchDeparture(observed, int(combo.DepartingTerminalID), int(combo.ArrivingTerminalID), *sailing.DepartingTime) {
+				continue
+			}
+
+			cancelled = append(cancelled, Departure{
+				Route:                 route,
+				VesselID:              int(sailing.VesselID),
+				VesselName:            sailing.VesselName,
+				DepartingTerminalID:   int(combo.DepartingTerminalID),
+				DepartingTerminalName: combo.DepartingTerminalName,
+				ArrivingTerminalID:    int(combo.ArrivingTerminalID),
+				ArrivingTerminalName:  combo.ArrivingTerminalName,
+				Scheduled:             *sailing.DepartingTime,
+				Cancelled:             true,
+			})
+		}
+	}
+
+	return cancelled
+}
+
+func matchDeparture(departures []Departure, departingTerminalID, arrivingTerminalID int, scheduled time.Time) bool {
+	for _, departure := range departures {
+		if departure.DepartingTerminalID != departingTerminalID || departure.ArrivingTerminalID != arrivingTerminalID {
+			continue
+		}
+
+		if d := departure.Scheduled.Sub(scheduled); d > -matchTolerance && d < matchTolerance {
+			return true
+		}
+	}
+
+	return false
+}
+
+type GroupBy int
+
+const (
+	ByRoute GroupBy = iota
+	ByTerminalPair
+	ByVessel
+	ByHourOfDay
+)
+
+type Stats struct {
+	Key           string
+	Sailings      int
+	OnTime        int
+	Late          int
+	Cancelled     int
+	OnTimePercent float64
+	AverageDelay  time.Duration
+	P95Delay      time.Duration
+}
+
+func groupKey(departure Departure, groupBy GroupBy) string {
+	switch groupBy {
+	case ByTerminalPair:
+		return departure.DepartingTerminalName + " to " + departure.ArrivingTerminalName
+	case ByVessel:
+		if departure.VesselName != "" {
+			return departure.VesselName
+		}
+		return strconv.Itoa(departure.VesselID)
+	case ByHourOfDay:
+		return fmt.Sprintf("%02d:00", departure.Scheduled.In(ferries.TimeZone()).Hour())
+	default:
+		return departure.Route
+	}
+}
+
+// Aggregate groups the departures and computes on-time performance for each
+// group, ordered by key. On-time percentage and delays only consider sailings
+// that operated; cancellations are counted separately.
+func Aggregate(departures []Departure, groupBy GroupBy, onTimeThreshold time.Duration) []Stats {
+	groups := make(map[string][]Departure)
+	for _, departure := range departures {
+		key := groupKey(departure, groupBy)
+		groups[key] = append(groups[key], departure)
+	}
+
+	stats := make([]Stats, 0, len(groups))
+	for key, group := range groups {
+		stats = append(stats, summarize(key, group, onTimeThreshold))
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Key < stats[j].Key
+	})
+
+	return stats
+}
+
+func summarize(key string, departures []Departure, onTimeThreshold time.Duration) Stats {
+	stats := Stats{
+		Key:      key,
+		Sailings: len(departures),
+	}
+
+	var (
+		delays []time.Duration
+		total  time.Duration
+	)
+	for _, departure := range departures {
+		if departure.Cancelled {
+			stats.Cancelled++
+			continue
+		}
+
+		delay := departure.Delay()
+		delays = append(delays, delay)
+		total += delay
+
+		if delay <= onTimeThreshold {
+			stats.OnTime++
+		} else {
+			stats.Late++
+		}
+	}
+
+	if len(delays) == 0 {
+		return stats
+	}
+
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+
+	stats.OnTimePercent = float64(stats.OnTime) / float64(len(delays)) * 100
+	stats.AverageDelay = total / time.Duration(len(delays))
+	stats.P95Delay = delays[int(math.Ceil(0.95*float64(len(delays))))-1]
+
+	return stats
+}
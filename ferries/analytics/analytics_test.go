@@ -0,0 +1,93 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+func departure(route string, vessel string, scheduled time.Time, delay time.Duration, cancelled bool) Departure {
+	d := Departure{
+		Route:                 route,
+		VesselName:            vessel,
+		DepartingTerminalID:   7,
+		DepartingTerminalName: "Seattle",
+		ArrivingTerminalID:    3,
+		ArrivingTerminalName:  "Bainbridge Island",
+		Scheduled:             scheduled,
+		Cancelled:             cancelled,
+	}
+	if !cancelled {
+		actual := scheduled.Add(delay)
+		d.Actual = &actual
+	}
+	return d
+}
+
+func TestAggregate(t *testing.T) {
+	base := time.Date(2024, 3, 1, 8, 0, 0, 0, ferries.TimeZone())
+
+	departures := []Departure{
+		departure("sea-bi", "Tacoma", base, 2*time.Minute, false),
+		departure("sea-bi", "Tacoma", base.Add(time.Hour), 12*time.Minute, false),
+		departure("sea-bi", "Wenatchee", base.Add(2*time.Hour), -time.Minute, false),
+		departure("sea-bi", "Wenatchee", base.Add(3*time.Hour), 0, true),
+		departure("ed-king", "Puyallup", base, 20*time.Minute, false),
+	}
+
+	byRoute := Aggregate(departures, ByRoute, DefaultOnTimeThreshold)
+	if len(byRoute) != 2 {
+		t.Fatalf("Aggregate() returned %d groups, want 2", len(byRoute))
+	}
+
+	seaBI := byRoute[1]
+	if seaBI.Key != "sea-bi" || seaBI.Sailings != 4 || seaBI.OnTime != 2 || seaBI.Late != 1 || seaBI.Cancelled != 1 {
+		t.Errorf("sea-bi stats = %+v", seaBI)
+	}
+	if got := int(seaBI.OnTimePercent + 0.5); got != 67 {
+		t.Errorf("sea-bi OnTimePercent = %v, want 66.7", seaBI.OnTimePercent)
+	}
+	if seaBI.AverageDelay != 14*time.Minute/3 {
+		t.Errorf("sea-bi AverageDelay = %v, want %v", seaBI.AverageDelay, 14*time.Minute/3)
+	}
+	if seaBI.P95Delay != 12*time.Minute {
+		t.Errorf("sea-bi P95Delay = %v, want 12m", seaBI.P95Delay)
+	}
+
+	byHour := Aggregate(departures, ByHourOfDay, DefaultOnTimeThreshold)
+	if len(byHour) != 4 || byHour[0].Key != "08:00" || byHour[0].Sailings != 2 {
+		t.Errorf("Aggregate(ByHourOfDay) = %+v", byHour)
+	}
+}
+
+func TestCancellations(t *testing.T) {
+	base := time.Date(2024, 3, 1, 8, 0, 0, 0, ferries.TimeZone())
+	at := func(d time.Duration) *time.Time {
+		t := base.Add(d)
+		return &t
+	}
+
+	schedule := ferries.Schedule{
+		TerminalCombos: []ferries.TerminalCombo{
+			{
+				DepartingTerminalID:   7,
+				DepartingTerminalName: "Seattle",
+				ArrivingTerminalID:    3,
+				ArrivingTerminalName:  "Bainbridge Island",
+				Times: []ferries.Time{
+					{DepartingTime: at(0), VesselName: "Tacoma"},
+					{DepartingTime: at(time.Hour), VesselName: "Tacoma"},
+					{DepartingTime: at(2 * time.Hour), VesselName: "Tacoma"},
+				},
+			},
+		},
+	}
+
+	observed := []Departure{departure("sea-bi", "Tacoma", base, 3*time.Minute, false)}
+
+	cancelled := Cancellations("sea-bi", schedule, observed, base.Add(90*time.Minute), 15*time.Minute)
+	if len(cancelled) != 1 || !cancelled[0].Scheduled.Equal(base.Add(time.Hour)) || !cancelled[0].Cancelled {
+		t.Errorf("Cancellations() = %+v, want only the 09:00 sailing", cancelled)
+	}
+}
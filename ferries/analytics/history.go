@@ -0,0 +1,185 @@
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+// History stores departures so performance can be reported over any period.
+type History interface {
+	Add(departures ...Departure) error
+	// Departures returns the departures scheduled within [from, to).
+	Departures(from, to time.Time) ([]Departure, error)
+}
+
+func inWindow(departure Departure, from, to time.Time) bool {
+	return !departure.Scheduled.Before(from) && departure.Scheduled.Before(to)
+}
+
+type MemoryHistory struct {
+	mu         sync.RWMutex
+	departures []Departure
+}
+
+func NewMemoryHistory() *MemoryHistory {
+	return &MemoryHistory{}
+}
+
+func (h *MemoryHistory) Add(departures ...Departure) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.departures = append(h.departures, departures...)
+
+	return nil
+}
+
+func (h *MemoryHistory) Departures(from, to time.Time) ([]Departure, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var departures []Departure
+	for _, departure := range h.departures {
+		if inWindow(departure, from, to) {
+			departures = append(departures, departure)
+		}
+	}
+
+	return departures, nil
+}
+
+// FileHistory appends departures to a file as JSON lines.
+type FileHistory struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileHistory(path string) *FileHistory {
+	return &FileHistory{
+		path: path,
+	}
+}
+
+func (h *FileHistory) Add(departures ...Departure) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening history: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, departure := range departures {
+		if err := encoder.Encode(departure); err != nil {
+			return fmt.Errorf("error writing history: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (h *FileHistory) Departures(from, to time.Time) ([]Departure, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	file, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening history: %v", err)
+	}
+	defer file.Close()
+
+	var departures []Departure
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var departure Departure
+		if err := json.Unmarshal(scanner.Bytes(), &departure); err != nil {
+			return nil, fmt.Errorf("error reading history: %v", err)
+		}
+
+		if inWindow(departure, from, to) {
+			departures = append(departures, departure)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+
+	return departures, nil
+}
+
+// Recorder feeds vessel events and schedules into a History.
+type Recorder struct {
+	history History
+}
+
+func NewRecorder(history History) *Recorder {
+	return &Recorder{
+		history: history,
+	}
+}
+
+// RecordEvent stores the departure carried by a VesselEventDeparted and
+// ignores every other event.
+func (r *Recorder) RecordEvent(event ferries.VesselEvent) error {
+	departure, ok := DepartureFromEvent(event)
+	if !ok {
+		return nil
+	}
+
+	return r.history.Add(departure)
+}
+
+// RecordCancellations stores a cancellation for every sailing in the schedule
+// that was due more than grace before asOf and was neither observed nor
+// already recorded as cancelled.
+func (r *Recorder) RecordCancellations(route string, schedule ferries.Schedule, asOf time.Time, grace time.Duration) error {
+	from, to := scheduleWindow(schedule)
+	if from.IsZero() {
+		return nil
+	}
+
+	recorded, err := r.history.Departures(from.Add(-matchTolerance), to.Add(matchTolerance))
+	if err != nil {
+		return err
+	}
+
+	cancelled := Cancellations(route, schedule, recorded, asOf, grace)
+	if len(cancelled) == 0 {
+		return nil
+	}
+
+	return r.history.Add(cancelled...)
+}
+
+func scheduleWindow(schedule ferries.Schedule) (time.Time, time.Time) {
+	var from, to time.Time
+	for _, combo := range schedule.TerminalCombos {
+		for _, sailing := range combo.Times {
+			if sailing.DepartingTime == nil {
+				continue
+			}
+
+			if from.IsZero() || sailing.DepartingTime.Before(from) {
+				from = *sailing.DepartingTime
+			}
+			if sailing.DepartingTime.After(to) {
+				to = *sailing.DepartingTime
+			}
+		}
+	}
+
+	return from, to
+}
@@ -0,0 +1,284 @@
+package ferries
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// retimeWindow is how far a sailing may move and still be reported as
+	// retimed rather than removed and added.
+	retimeWindow = 90 * time.Minute
+)
+
+// Sailing is a scheduled sailing with its annotations resolved to text.
+type Sailing struct {
+	Time
+	Annotations []string
+}
+
+type SailingChange struct {
+	Old                Sailing
+	New                Sailing
+	Retimed            bool
+	VesselReassigned   bool
+	AnnotationsChanged bool
+}
+
+type ComboDiff struct {
+	DepartingTerminalID   int64
+	DepartingTerminalName string
+	ArrivingTerminalID    int64
+	ArrivingTerminalName  string
+	Added                 []Sailing
+	Removed               []Sailing
+	Changed               []SailingChange
+}
+
+func (d ComboDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ScheduleDiff holds the terminal combos that changed, ordered by departing
+// and then arriving terminal.
+type ScheduleDiff struct {
+	Combos []ComboDiff
+}
+
+func (d ScheduleDiff) Empty() bool {
+	return len(d.Combos) == 0
+}
+
+type comboKey struct {
+	departing int64
+	arriving  int64
+}
+
+// CompareSchedules compares two schedules, typically different days or
+// seasons of the same route, one terminal combo at a time. Sailings are
+// matched by their Pacific time of day within the service day. Unmatched
+// sailings of the same VesselPositionNum within 90 minutes of each other are
+// reported as retimed; the rest as added or removed.
+func CompareSchedules(before, after Schedule) ScheduleDiff {
+	oldCombos := make(map[comboKey]TerminalCombo)
+	for _, combo := range before.TerminalCombos {
+		oldCombos[comboKey{combo.DepartingTerminalID, combo.ArrivingTerminalID}] = combo
+	}
+
+	newCombos := make(map[comboKey]TerminalCombo)
+	for _, combo := range after.TerminalCombos {
+		newCombos[comboKey{combo.DepartingTerminalID, combo.ArrivingTerminalID}] = combo
+	}
+
+	keys := make(map[comboKey]struct{})
+	for key := range oldCombos {
+		keys[key] = struct{}{}
+	}
+	for key := range newCombos {
+		keys[key] = struct{}{}
+	}
+
+	var diff ScheduleDiff
+	for key := range keys {
+		oldCombo, newCombo := oldCombos[key], newCombos[key]
+
+		comboDiff := compareCombos(oldCombo, newCombo)
+		comboDiff.DepartingTerminalID, comboDiff.ArrivingTerminalID = key.departing, key.arriving
+		comboDiff.DepartingTerminalName = firstNonEmpty(newCombo.DepartingTerminalName, oldCombo.DepartingTerminalName)
+		comboDiff.ArrivingTerminalName = firstNonEmpty(newCombo.ArrivingTerminalName, oldCombo.ArrivingTerminalName)
+
+		if !comboDiff.Empty() {
+			diff.Combos = append(diff.Combos, comboDiff)
+		}
+	}
+
+	sort.Slice(diff.Combos, func(i, j int) bool {
+		if diff.Combos[i].DepartingTerminalID != diff.Combos[j].DepartingTerminalID {
+			return diff.Combos[i].DepartingTerminalID < diff.Combos[j].DepartingTerminalID
+		}
+		return diff.Combos[i].ArrivingTerminalID < diff.Combos[j].ArrivingTerminalID
+	})
+
+	return diff
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func comboSailings(combo TerminalCombo) []Sailing {
+	var sailings []Sailing
+	for _, t := range combo.Times {
+		if t.DepartingTime == nil {
+			continue
+		}
+
+		sailing := Sailing{Time: t}
+		for _, index := range t.AnnotationIndexes {
+			if index >= 0 && index < len(combo.Annotations) {
+				sailing.Annotations = append(sailing.Annotations, strings.TrimSpace(combo.Annotations[index]))
+			}
+		}
+
+		sailings = append(sailings, sailing)
+	}
+
+	sort.SliceStable(sailings, func(i, j int) bool {
+		return sailings[i].DepartingTime.Before(*sailings[j].DepartingTime)
+	})
+
+	return sailings
+}
+
+// timeOfDay is the Pacific wall clock time of the sailing measured from the
+// start of its service day, so sailings on different days can be matched and
+// a sailing at 12:50 AM counts as 24:50, after the previous evening's.
+func timeOfDay(sailing Sailing) time.Duration {
+	local := sailing.DepartingTime.In(TimeZone())
+
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if offset < ServiceDayCutoff {
+		offset += 24 * time.Hour
+	}
+
+	return offset
+}
+
+func compareCombos(before, after TerminalCombo) ComboDiff {
+	oldSailings, newSailings := comboSailings(before), comboSailings(after)
+	oldMatched, newMatched := make([]bool, len(oldSailings)), make([]bool, len(newSailings))
+
+	var diff ComboDiff
+
+	change := func(o, n Sailing) {
+		c := SailingChange{
+			Old:                o,
+			New:                n,
+			Retimed:            timeOfDay(o) != timeOfDay(n),
+			VesselReassigned:   o.VesselID != n.VesselID,
+			AnnotationsChanged: !slices.Equal(o.Annotations, n.Annotations),
+		}
+
+		if c.Retimed || c.VesselReassigned || c.AnnotationsChanged {
+			diff.Changed = append(diff.Changed, c)
+		}
+	}
+
+	for i, o := range oldSailings {
+		for j, n := range newSailings {
+			if newMatched[j] || timeOfDay(o) != timeOfDay(n) {
+				continue
+			}
+
+			oldMatched[i], newMatched[j] = true, true
+			change(o, n)
+			break
+		}
+	}
+
+	for i, o := range oldSailings {
+		if oldMatched[i] || o.VesselPositionNum == 0 {
+			continue
+		}
+
+		for j, n := range newSailings {
+			if newMatched[j] || n.VesselPositionNum != o.VesselPositionNum {
+				continue
+			}
+
+			if d := timeOfDay(n) - timeOfDay(o); d < -retimeWindow || d > retimeWindow {
+				continue
+			}
+
+			oldMatched[i], newMatched[j] = true, true
+			change(o, n)
+			break
+		}
+	}
+
+	for i, o := range oldSailings {
+		if !oldMatched[i] {
+			diff.Removed = append(diff.Removed, o)
+		}
+	}
+	for j, n := range newSailings {
+		if !newMatched[j] {
+			diff.Added = append(diff.Added, n)
+		}
+	}
+
+	sort.SliceStable(diff.Changed, func(i, j int) bool {
+		return timeOfDay(diff.Changed[i].Old) < timeOfDay(diff.Changed[j].Old)
+	})
+
+	return diff
+}
+
+func formatSailingTime(sailing Sailing) string {
+	return sailing.DepartingTime.In(TimeZone()).Format("3:04 PM")
+}
+
+func formatSailing(sailing Sailing) string {
+	s := formatSailingTime(sailing)
+	if sailing.VesselName != "" {
+		s += " " + sailing.VesselName
+	}
+	if len(sailing.Annotations) > 0 {
+		s += " (" + strings.Join(sailing.Annotations, "; ") + ")"
+	}
+
+	return s
+}
+
+// String renders the diff as a report, one section per terminal combo:
+//
+//	Seattle to Bainbridge Island
+//	  + 9:05 PM Wenatchee
+//	  - 5:30 AM Tacoma
+//	  ~ 6:20 AM retimed to 6:25 AM
+func (d ScheduleDiff) String() string {
+	if d.Empty() {
+		return "No changes\n"
+	}
+
+	var b strings.Builder
+	for i, combo := range d.Combos {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s to %s\n", combo.DepartingTerminalName, combo.ArrivingTerminalName)
+
+		for _, sailing := range combo.Added {
+			fmt.Fprintf(&b, "  + %s\n", formatSailing(sailing))
+		}
+		for _, sailing := range combo.Removed {
+			fmt.Fprintf(&b, "  - %s\n", formatSailing(sailing))
+		}
+
+		for _, c := range combo.Changed {
+			var parts []string
+			if c.Retimed {
+				parts = append(parts, "retimed to "+formatSailingTime(c.New))
+			}
+			if c.VesselReassigned {
+				parts = append(parts, fmt.Sprintf("vessel %s to %s", c.Old.VesselName, c.New.VesselName))
+			}
+			if c.AnnotationsChanged {
+				parts = append(parts, fmt.Sprintf("notes %q to %q", strings.Join(c.Old.Annotations, "; "), strings.Join(c.New.Annotations, "; ")))
+			}
+
+			fmt.Fprintf(&b, "  ~ %s %s\n", formatSailingTime(c.Old), strings.Join(parts, ", "))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package ferries
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareSchedules(t *testing.T) {
+	at := func(day, hour, minute int) *time.Time {
+		t := time.Date(2024, 6, day, hour, minute, 0, 0, TimeZone())
+		return &t
+	}
+
+	before := Schedule{
+		TerminalCombos: []TerminalCombo{
+			{
+				DepartingTerminalID: 7, DepartingTerminalName: "Seattle",
+				ArrivingTerminalID: 3, ArrivingTerminalName: "Bainbridge Island",
+				Annotations: []string{"No vehicles"},
+				Times: []Time{
+					{DepartingTime: at(1, 5, 30), VesselID: 1, VesselName: "Tacoma", VesselPositionNum: 1},
+					{DepartingTime: at(1, 6, 20), VesselID: 2, VesselName: "Wenatchee", VesselPositionNum: 2},
+					{DepartingTime: at(1, 7, 55), VesselID: 1, VesselName: "Tacoma", VesselPositionNum: 1},
+					{DepartingTime: at(1, 8, 45), VesselID: 2, VesselName: "Wenatchee", VesselPositionNum: 2},
+					{DepartingTime: at(1, 22, 0), VesselID: 1, VesselName: "Tacoma", VesselPositionNum: 1},
+				},
+			},
+		},
+	}
+
+	after := Schedule{
+		TerminalCombos: []TerminalCombo{
+			{
+				DepartingTerminalID: 7, DepartingTerminalName: "Seattle",
+				ArrivingTerminalID: 3, ArrivingTerminalName: "Bainbridge Island",
+				Annotations: []string{"No vehicles"},
+				Times: []Time{
+					{DepartingTime: at(8, 5, 30), VesselID: 1, VesselName: "Tacoma", VesselPositionNum: 1},
+					{DepartingTime: at(8, 6, 25), VesselID: 2, VesselName: "Wenatchee", VesselPositionNum: 2},
+					{DepartingTime: at(8, 7, 55), VesselID: 3, VesselName: "Kitsap", VesselPositionNum: 1},
+					{DepartingTime: at(8, 8, 45), VesselID: 2, VesselName: "Wenatchee", VesselPositionNum: 2, AnnotationIndexes: []int{0}},
+					{DepartingTime: at(8, 23, 30), VesselID: 2, VesselName: "Wenatchee", VesselPositionNum: 2},
+				},
+			},
+			{
+				DepartingTerminalID: 3, DepartingTerminalName: "Bainbridge Island",
+				ArrivingTerminalID: 7, ArrivingTerminalName: "Seattle",
+				Times: []Time{{DepartingTime: at(8, 4, 45), VesselID: 1, VesselName: "Tacoma"}},
+			},
+		},
+	}
+
+	diff := CompareSchedules(before, after)
+	if len(diff.Combos) != 2 {
+		t.Fatalf("CompareSchedules() combos = %d, want 2", len(diff.Combos))
+	}
+
+	returning, outbound := diff.Combos[0], diff.Combos[1]
+	if len(returning.Added) != 1 || len(returning.Removed) != 0 {
+		t.Errorf("CompareSchedules() new combo added = %d removed = %d, want 1 0", len(returning.Added), len(returning.Removed))
+	}
+
+	if len(outbound.Added) != 1 || outbound.Added[0].VesselName != "Wenatchee" {
+		t.Errorf("CompareSchedules() added = %+v, want the 11:30 PM Wenatchee", outbound.Added)
+	}
+	if len(outbound.Removed) != 1 || outbound.Removed[0].VesselName != "Tacoma" {
+		t.Errorf("CompareSchedules() removed = %+v, want the 10:00 PM Tacoma", outbound.Removed)
+	}
+
+	if len(outbound.Changed) != 3 {
+		t.Fatalf("CompareSchedules() changed = %d, want 3", len(outbound.Changed))
+	}
+	if c := outbound.Changed[0]; !c.Retimed || c.VesselReassigned || c.AnnotationsChanged {
+		t.Errorf("CompareSchedules() 6:20 AM change = %+v, want retimed", c)
+	}
+	if c := outbound.Changed[1]; c.Retimed || !c.VesselReassigned || c.AnnotationsChanged {
+		t.Errorf("CompareSchedules() 7:55 AM change = %+v, want vessel reassigned", c)
+	}
+	if c := outbound.Changed[2]; c.Retimed || c.VesselReassigned || !c.AnnotationsChanged {
+		t.Errorf("CompareSchedules() 8:45 AM change = %+v, want annotations changed", c)
+	}
+
+	report := diff.String()
+	for _, want := range []string{
+		"Seattle to Bainbridge Island\n",
+		"  + 11:30 PM Wenatchee\n",
+		"  - 10:00 PM Tacoma\n",
+		"  ~ 6:20 AM retimed to 6:25 AM\n",
+		"  ~ 7:55 AM vessel Tacoma to Kitsap\n",
+		`  ~ 8:45 AM notes "" to "No vehicles"`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("String() missing %q in\n%s", want, report)
+		}
+	}
+
+	if !CompareSchedules(before, before).Empty() {
+		t.Errorf("CompareSchedules() of identical schedules is not empty")
+	}
+}
+
+func TestCompareSchedulesAfterMidnight(t *testing.T) {
+	at := func(day, hour, minute int) *time.Time {
+		t := time.Date(2024, 6, day, hour, minute, 0, 0, TimeZone())
+		return &t
+	}
+
+	combo := func(times ...Time) Schedule {
+		return Schedule{
+			TerminalCombos: []TerminalCombo{
+				{
+					DepartingTerminalID: 7, DepartingTerminalName: "Seattle",
+					ArrivingTerminalID: 3, ArrivingTerminalName: "Bainbridge Island",
+					Times: times,
+				},
+			},
+		}
+	}
+
+	before := combo(
+		Time{DepartingTime: at(1, 5, 30), VesselID: 1, VesselName: "Tacoma", VesselPositionNum: 1},
+		Time{DepartingTime: at(1, 23, 50), VesselID: 1, VesselName: "Tacoma", VesselPositionNum: 1},
+		Time{DepartingTime: at(2, 0, 50), VesselID: 2, VesselName: "Wenatchee", VesselPositionNum: 2},
+	)
+	after := combo(
+		Time{DepartingTime: at(8, 5, 30), VesselID: 3, VesselName: "Kitsap", VesselPositionNum: 1},
+		Time{DepartingTime: at(9, 0, 10), VesselID: 1, VesselName: "Tacoma", VesselPositionNum: 1},
+		Time{DepartingTime: at(9, 0, 50), VesselID: 3, VesselName: "Kitsap", VesselPositionNum: 2},
+	)
+
+	diff := CompareSchedules(before, after)
+	if len(diff.Combos) != 1 {
+		t.Fatalf("CompareSchedules() combos = %d, want 1", len(diff.Combos))
+	}
+
+	outbound := diff.Combos[0]
+	if len(outbound.Added) != 0 || len(outbound.Removed) != 0 {
+		t.Errorf("CompareSchedules() added = %+v removed = %+v, want none", outbound.Added, outbound.Removed)
+	}
+
+	// changes are ordered through the service day: the 12:50 AM sailing
+	// follows the 11:50 PM one
+	want := []string{"5:30 AM", "11:50 PM", "12:50 AM"}
+	if len(outbound.Changed) != len(want) {
+		t.Fatalf("CompareSchedules() changed = %d, want %d", len(outbound.Changed), len(want))
+	}
+	for i, c := range outbound.Changed {
+		if got := formatSailingTime(c.Old); got != want[i] {
+			t.Errorf("CompareSchedules() change %d = %s, want %s", i, got, want[i])
+		}
+	}
+
+	if c := outbound.Changed[1]; !c.Retimed || formatSailingTime(c.New) != "12:10 AM" || c.VesselReassigned {
+		t.Errorf("CompareSchedules() 11:50 PM change = %+v, want retimed to 12:10 AM", c)
+	}
+}
@@ -0,0 +1,157 @@
+package ferries
+
+import (
+	"sort"
+	"time"
+)
+
+// From parses DateFrom, the first day the adjustment applies.
+func (a ContingencyAdjustment) From() (*time.Time, error) {
+	return wsdotTimeStringToTime(a.DateFrom)
+}
+
+// Thru parses DateThru, the last day the adjustment applies.
+func (a ContingencyAdjustment) Thru() (*time.Time, error) {
+	return wsdotTimeStringToTime(a.DateThru)
+}
+
+// AppliesOn reports whether date falls on or between the Pacific days of
+// DateFrom and DateThru. Adjustments with unparsable dates never apply.
+func (a ContingencyAdjustment) AppliesOn(date time.Time) bool {
+	from, err := a.From()
+	if err != nil {
+		return false
+	}
+
+	thru, err := a.Thru()
+	if err != nil {
+		return false
+	}
+
+	day := pacificDay(date)
+
+	return !day.Before(pacificDay(*from)) && !day.After(pacificDay(*thru))
+}
+
+func pacificDay(t time.Time) time.Time {
+	local := t.In(TimeZone())
+
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, TimeZone())
+}
+
+// EffectiveRoute is the sched route a route runs on a given day.
+type EffectiveRoute struct {
+	RouteID     int
+	RouteAbbrev string
+	// SchedRoute is nil when the route is cancelled without a replacement.
+	SchedRoute *RouteSchedule
+	Cancelled  bool
+	// Adjustment is the contingency adjustment that changed the route's
+	// regular sched route, if any.
+	Adjustment *ContingencyAdjustment
+}
+
+// ResolveEffectiveSchedules applies the contingency adjustments of the route
+// schedules, as returned by GetRouteSchedules, to find the sched route each
+// route runs on date:
+//
+//   - a cancellation of the regular sched route switches the route to its
+//     ReplacedBySchedRouteID, or cancels the route when there is none
+//   - a contingency-only sched route with an addition covering date replaces
+//     the regular sched route
+//   - contingency-only sched routes are otherwise ignored
+//
+// Routes are returned ordered by RouteID.
+func ResolveEffectiveSchedules(routes []RouteSchedule, date time.Time) []EffectiveRoute {
+	bySchedRouteID := make(map[int]*RouteSchedule, len(routes))
+	for i := range routes {
+		bySchedRouteID[routes[i].SchedRouteID] = &routes[i]
+	}
+
+	effective := make(map[int]*EffectiveRoute)
+
+	// regular sched routes and their cancellations first, so additions can
+	// take precedence over them below
+	for i := range routes {
+		route := &routes[i]
+		if route.ContingencyOnly {
+			continue
+		}
+
+		if _, ok := effective[route.RouteID]; ok {
+			continue
+		}
+
+		resolved := &EffectiveRoute{
+			RouteID:     route.RouteID,
+			RouteAbbrev: route.RouteAbbrev,
+			SchedRoute:  route,
+		}
+
+		for j := range route.ContingencyAdj {
+			adjustment := &route.ContingencyAdj[j]
+			if adjustment.AdjType != AdjTypeCancellation || !adjustment.AppliesOn(date) {
+				continue
+			}
+
+			resolved.Adjustment = adjustment
+			resolved.SchedRoute = nil
+			resolved.Cancelled = true
+
+			if adjustment.ReplacedBySchedRouteID != nil {
+				if replacement, ok := bySchedRouteID[*adjustment.ReplacedBySchedRouteID]; ok {
+					resolved.SchedRoute = replacement
+					resolved.Cancelled = false
+				}
+			}
+
+			break
+		}
+
+		effective[route.RouteID] = resolved
+	}
+
+	for i := range routes {
+		route := &routes[i]
+		if !route.ContingencyOnly {
+			continue
+		}
+
+		for j := range route.ContingencyAdj {
+			adjustment := &route.ContingencyAdj[j]
+			if adjustment.AdjType != AdjTypeAddition || !adjustment.AppliesOn(date) {
+				continue
+			}
+
+			resolved, ok := effective[route.RouteID]
+			if !ok {
+				resolved = &EffectiveRoute{RouteID: route.RouteID, RouteAbbrev: route.RouteAbbrev}
+				effective[route.RouteID] = resolved
+			}
+
+			// a replacement named by the cancellation wins over other additions
+			if resolved.SchedRoute != nil && resolved.SchedRoute.ContingencyOnly {
+				break
+			}
+
+			resolved.SchedRoute = route
+			resolved.Cancelled = false
+			if resolved.Adjustment == nil {
+				resolved.Adjustment = adjustment
+			}
+
+			break
+		}
+	}
+
+	resolved := make([]EffectiveRoute, 0, len(effective))
+	for _, route := range effective {
+		resolved = append(resolved, *route)
+	}
+
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].RouteID < resolved[j].RouteID
+	})
+
+	return resolved
+}
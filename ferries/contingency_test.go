@@ -0,0 +1,80 @@
+package ferries
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveEffectiveSchedules(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 6, d, 12, 0, 0, 0, TimeZone())
+	}
+	adjustment := func(adjType AdjType, from, thru int, replacedBy *int) ContingencyAdjustment {
+		return ContingencyAdjustment{
+			DateFrom:               wsdotTime(time.Date(2024, 6, from, 0, 0, 0, 0, TimeZone())),
+			DateThru:               wsdotTime(time.Date(2024, 6, thru, 23, 59, 0, 0, TimeZone())),
+			AdjType:                adjType,
+			ReplacedBySchedRouteID: replacedBy,
+		}
+	}
+	replacement := 101
+
+	routes := []RouteSchedule{
+		{SchedRouteID: 100, RouteID: 5, RouteAbbrev: "sea-bi", ContingencyAdj: []ContingencyAdjustment{adjustment(AdjTypeCancellation, 10, 12, &replacement)}},
+		{SchedRouteID: 101, RouteID: 5, RouteAbbrev: "sea-bi", ContingencyOnly: true, ContingencyAdj: []ContingencyAdjustment{adjustment(AdjTypeAddition, 10, 12, nil)}},
+		{SchedRouteID: 200, RouteID: 6, RouteAbbrev: "ed-king", ContingencyAdj: []ContingencyAdjustment{adjustment(AdjTypeCancellation, 11, 11, nil)}},
+		{SchedRouteID: 300, RouteID: 7, RouteAbbrev: "muk-cl"},
+		{SchedRouteID: 301, RouteID: 7, RouteAbbrev: "muk-cl", ContingencyOnly: true, ContingencyAdj: []ContingencyAdjustment{adjustment(AdjTypeAddition, 20, 21, nil)}},
+	}
+
+	type want struct {
+		schedRouteID int
+		cancelled    bool
+		adjusted     bool
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want map[int]want
+	}{
+		{
+			name: "Regular schedules",
+			date: day(1),
+			want: map[int]want{5: {schedRouteID: 100}, 6: {schedRouteID: 200}, 7: {schedRouteID: 300}},
+		},
+		{
+			name: "Replaced and cancelled",
+			date: day(11),
+			want: map[int]want{5: {schedRouteID: 101, adjusted: true}, 6: {cancelled: true, adjusted: true}, 7: {schedRouteID: 300}},
+		},
+		{
+			name: "Contingency addition",
+			date: day(21),
+			want: map[int]want{5: {schedRouteID: 100}, 6: {schedRouteID: 200}, 7: {schedRouteID: 301, adjusted: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ResolveEffectiveSchedules(routes, tt.date)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ResolveEffectiveSchedules() = %d routes, want %d", len(got), len(tt.want))
+			}
+
+			for _, route := range got {
+				w := tt.want[route.RouteID]
+
+				schedRouteID := 0
+				if route.SchedRoute != nil {
+					schedRouteID = route.SchedRoute.SchedRouteID
+				}
+
+				if schedRouteID != w.schedRouteID || route.Cancelled != w.cancelled || (route.Adjustment != nil) != w.adjusted {
+					t.Errorf("ResolveEffectiveSchedules() route %d = sched route %d, cancelled %v, adjusted %v, want %+v",
+						route.RouteID, schedRouteID, route.Cancelled, route.Adjustment != nil, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package ferries
+
+import (
+	"sort"
+	"time"
+)
+
+// LoadingRule is who a sailing boards.
+type LoadingRule int
+
+const (
+	LoadingRulePassenger LoadingRule = 1
+	LoadingRuleVehicle   LoadingRule = 2
+	LoadingRuleBoth      LoadingRule = 3
+)
+
+func (r LoadingRule) String() string {
+	switch r {
+	case LoadingRulePassenger:
+		return "passenger"
+	case LoadingRuleVehicle:
+		return "vehicle"
+	case LoadingRuleBoth:
+		return "both"
+	default:
+		return "unknown"
+	}
+}
+
+// SailingFilter selects sailings in departure queries.
+type SailingFilter func(Time) bool
+
+// HandicapAccessible selects sailings on accessible vessels.
+func HandicapAccessible() SailingFilter {
+	return func(t Time) bool {
+		return t.VesselHandicapAccessible
+	}
+}
+
+// Carries selects sailings that board rule, so Carries(LoadingRuleVehicle)
+// matches vehicle-only sailings as well as sailings loading both.
+func Carries(rule LoadingRule) SailingFilter {
+	return func(t Time) bool {
+		return t.LoadingRule == rule || t.LoadingRule == LoadingRuleBoth
+	}
+}
+
+func matches(sailing Time, filters []SailingFilter) bool {
+	for _, filter := range filters {
+		if !filter(sailing) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sailings returns the combo's sailings that pass the filters, ordered by
+// departure. Sailings are ordered by their absolute time, so those after
+// midnight come last even though their clock time is earliest.
+func (c TerminalCombo) sailings(filters []SailingFilter) []Time {
+	var sailings []Time
+	for _, sailing := range c.Times {
+		if sailing.DepartingTime != nil && matches(sailing, filters) {
+			sailings = append(sailings, sailing)
+		}
+	}
+
+	sort.SliceStable(sailings, func(i, j int) bool {
+		return sailings[i].DepartingTime.Before(*sailings[j].DepartingTime)
+	})
+
+	return sailings
+}
+
+// NextDepartures returns up to n sailings departing at or after after.
+func (c TerminalCombo) NextDepartures(after time.Time, n int, filters ...SailingFilter) []Time {
+	var departures []Time
+	for _, sailing := range c.sailings(filters) {
+		if len(departures) == n {
+			break
+		}
+
+		if !sailing.DepartingTime.Before(after) {
+			departures = append(departures, sailing)
+		}
+	}
+
+	return departures
+}
+
+// LastSailing returns the last sailing of the schedule day, including
+// sailings after midnight.
+func (c TerminalCombo) LastSailing(filters ...SailingFilter) (Time, bool) {
+	sailings := c.sailings(filters)
+	if len(sailings) == 0 {
+		return Time{}, false
+	}
+
+	return sailings[len(sailings)-1], true
+}
+
+// SailingsBetween returns the sailings departing within [from, to).
+func (c TerminalCombo) SailingsBetween(from, to time.Time, filters ...SailingFilter) []Time {
+	var sailings []Time
+	for _, sailing := range c.sailings(filters) {
+		if !sailing.DepartingTime.Before(from) && sailing.DepartingTime.Before(to) {
+			sailings = append(sailings, sailing)
+		}
+	}
+
+	return sailings
+}
+
+// Combo returns the terminal combo from one terminal to another.
+func (s Schedule) Combo(departingTerminalID, arrivingTerminalID int64) (TerminalCombo, bool) {
+	for _, combo := range s.TerminalCombos {
+		if combo.DepartingTerminalID == departingTerminalID && combo.ArrivingTerminalID == arrivingTerminalID {
+			return combo, true
+		}
+	}
+
+	return TerminalCombo{}, false
+}
+
+// NextDepartures returns up to n sailings between the terminals departing at
+// or after after.
+func (s Schedule) NextDepartures(departingTerminalID, arrivingTerminalID int64, after time.Time, n int, filters ...SailingFilter) []Time {
+	combo, ok := s.Combo(departingTerminalID, arrivingTerminalID)
+	if !ok {
+		return nil
+	}
+
+	return combo.NextDepartures(after, n, filters...)
+}
+
+func (s Schedule) LastSailing(departingTerminalID, arrivingTerminalID int64, filters ...SailingFilter) (Time, bool) {
+	combo, ok := s.Combo(departingTerminalID, arrivingTerminalID)
+	if !ok {
+		return Time{}, false
+	}
+
+	return combo.LastSailing(filters...)
+}
+
+func (s Schedule) SailingsBetween(departingTerminalID, arrivingTerminalID int64, from, to time.Time, filters ...SailingFilter) []Time {
+	combo, ok := s.Combo(departingTerminalID, arrivingTerminalID)
+	if !ok {
+		return nil
+	}
+
+	return combo.SailingsBetween(from, to, filters...)
+}
@@ -0,0 +1,109 @@
+package ferries
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDepartureQueries(t *testing.T) {
+	at := func(day, hour, minute int) *time.Time {
+		t := time.Date(2024, 6, day, hour, minute, 0, 0, TimeZone())
+		return &t
+	}
+
+	schedule := Schedule{
+		TerminalCombos: []TerminalCombo{
+			{
+				DepartingTerminalID: 7,
+				ArrivingTerminalID:  3,
+				// WSF lists sailings after midnight first by clock time
+				Times: []Time{
+					{DepartingTime: at(2, 0, 50), LoadingRule: LoadingRuleBoth, VesselHandicapAccessible: true},
+					{DepartingTime: at(1, 5, 30), LoadingRule: LoadingRuleBoth, VesselHandicapAccessible: true},
+					{DepartingTime: at(1, 6, 20), LoadingRule: LoadingRulePassenger},
+					{DepartingTime: at(1, 7, 55), LoadingRule: LoadingRuleVehicle, VesselHandicapAccessible: true},
+					{DepartingTime: at(1, 22, 0), LoadingRule: LoadingRuleBoth},
+				},
+			},
+		},
+	}
+
+	departures := func(times []Time) []time.Time {
+		var got []time.Time
+		for _, t := range times {
+			got = append(got, *t.DepartingTime)
+		}
+		return got
+	}
+
+	equal := func(got []time.Time, want ...*time.Time) bool {
+		if len(got) != len(want) {
+			return false
+		}
+		for i := range got {
+			if !got[i].Equal(*want[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	tests := []struct {
+		name string
+		got  []time.Time
+		want []*time.Time
+	}{
+		{
+			name: "Next departures",
+			got:  departures(schedule.NextDepartures(7, 3, *at(1, 6, 0), 2)),
+			want: []*time.Time{at(1, 6, 20), at(1, 7, 55)},
+		},
+		{
+			name: "Next departures include departure at the time",
+			got:  departures(schedule.NextDepartures(7, 3, *at(1, 6, 20), 1)),
+			want: []*time.Time{at(1, 6, 20)},
+		},
+		{
+			name: "Next departures past midnight",
+			got:  departures(schedule.NextDepartures(7, 3, *at(1, 21, 0), 5)),
+			want: []*time.Time{at(1, 22, 0), at(2, 0, 50)},
+		},
+		{
+			name: "Accessible vehicle sailings",
+			got:  departures(schedule.NextDepartures(7, 3, *at(1, 0, 0), 5, HandicapAccessible(), Carries(LoadingRuleVehicle))),
+			want: []*time.Time{at(1, 5, 30), at(1, 7, 55), at(2, 0, 50)},
+		},
+		{
+			name: "Passenger sailings between",
+			got:  departures(schedule.SailingsBetween(7, 3, *at(1, 5, 0), *at(1, 22, 0), Carries(LoadingRulePassenger))),
+			want: []*time.Time{at(1, 5, 30), at(1, 6, 20)},
+		},
+		{
+			name: "Unknown combo",
+			got:  departures(schedule.NextDepartures(3, 7, *at(1, 0, 0), 5)),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !equal(tt.got, tt.want...) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+
+	last, ok := schedule.LastSailing(7, 3)
+	if !ok || !last.DepartingTime.Equal(*at(2, 0, 50)) {
+		t.Errorf("LastSailing() = %v, %v, want %v", last.DepartingTime, ok, at(2, 0, 50))
+	}
+
+	last, ok = schedule.LastSailing(7, 3, Carries(LoadingRulePassenger), HandicapAccessible())
+	if !ok || !last.DepartingTime.Equal(*at(2, 0, 50)) {
+		t.Errorf("LastSailing() accessible passenger = %v, %v, want %v", last.DepartingTime, ok, at(2, 0, 50))
+	}
+
+	if _, ok := schedule.LastSailing(3, 7); ok {
+		t.Errorf("LastSailing() unknown combo ok = true, want false")
+	}
+}
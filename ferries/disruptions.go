@@ -0,0 +1,441 @@
+package ferries
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"alpineworks.io/wsdot"
+)
+
+const (
+	DefaultDisruptionWatcherInterval   = time.Minute
+	DefaultDisruptionWatcherMaxBackoff = 10 * time.Minute
+)
+
+// PublishTime parses PublishDate.
+func (d ServiceDisruption) PublishTime() (*time.Time, error) {
+	return wsdotTimeStringToTime(d.PublishDate)
+}
+
+// RouteDisruption is a service disruption with every route it was posted to.
+type RouteDisruption struct {
+	ServiceDisruption
+	RouteIDs []int
+}
+
+func (d RouteDisruption) equal(other RouteDisruption) bool {
+	return d.ServiceDisruption == other.ServiceDisruption && slices.Equal(d.RouteIDs, other.RouteIDs)
+}
+
+// TerminalDisruption is a bulletin posted to a terminal.
+type TerminalDisruption struct {
+	Bulletin
+	TerminalID   int
+	TerminalName string
+}
+
+type terminalDisruptionKey struct {
+	terminalID int
+	title      string
+}
+
+func (d TerminalDisruption) key() terminalDisruptionKey {
+	return terminalDisruptionKey{d.TerminalID, d.BulletinTitle}
+}
+
+type DisruptionEventType int
+
+const (
+	DisruptionAdded DisruptionEventType = iota
+	DisruptionUpdated
+	DisruptionRemoved
+)
+
+func (t DisruptionEventType) String() string {
+	switch t {
+	case DisruptionAdded:
+		return "added"
+	case DisruptionUpdated:
+		return "updated"
+	case DisruptionRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+type DisruptionEvent struct {
+	Type       DisruptionEventType
+	Disruption RouteDisruption
+	// Previous is the disruption before an update.
+	Previous *RouteDisruption
+	// Terminal is set instead of Disruption for terminal bulletins, with
+	// PreviousTerminal holding the bulletin before an update.
+	Terminal         *TerminalDisruption
+	PreviousTerminal *TerminalDisruption
+	// Published is the parsed PublishDate, or BulletinLastUpdated for terminal
+	// bulletins, nil when it could not be parsed.
+	Published *time.Time
+}
+
+// DisruptionWatcher polls GetRouteSchedules and delivers an event whenever a
+// service disruption bulletin is posted, edited or taken down. Bulletins are
+// tracked by BulletinID across all routes. With WithTerminalBulletins it also
+// polls GetTerminalBulletins, tracking terminal bulletins by terminal and
+// title. The first poll only records a baseline unless the watcher is seeded
+// with WithInitialDisruptions or WithInitialTerminalDisruptions.
+type DisruptionWatcher struct {
+	interval          time.Duration
+	maxBackoff        time.Duration
+	errorHandler      func(error)
+	hasBaseline       bool
+	terminalBulletins bool
+
+	getRouteSchedules    func() ([]RouteSchedule, error)
+	getTerminalBulletins func() ([]TerminalBulletin, error)
+
+	events chan DisruptionEvent
+
+	mu          sync.RWMutex
+	disruptions map[int]RouteDisruption
+	terminals   map[terminalDisruptionKey]TerminalDisruption
+}
+
+type DisruptionWatcherOption func(*DisruptionWatcher)
+
+func NewDisruptionWatcher(ferriesClient *FerriesClient, options ...DisruptionWatcherOption) (*DisruptionWatcher, error) {
+	if ferriesClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	watcher := &DisruptionWatcher{
+		interval:          DefaultDisruptionWatcherInterval,
+		maxBackoff:        DefaultDisruptionWatcherMaxBackoff,
+		getRouteSchedules: ferriesClient.GetRouteSchedules,
+		events:            make(chan DisruptionEvent, 32),
+		disruptions:       make(map[int]RouteDisruption),
+		terminals:         make(map[terminalDisruptionKey]TerminalDisruption),
+	}
+
+	for _, option := range options {
+		option(watcher)
+	}
+
+	if watcher.terminalBulletins {
+		watcher.getTerminalBulletins = ferriesClient.GetTerminalBulletins
+	}
+
+	return watcher, nil
+}
+
+func WithDisruptionWatcherInterval(interval time.Duration) DisruptionWatcherOption {
+	return func(w *DisruptionWatcher) {
+		w.interval = interval
+	}
+}
+
+// WithDisruptionWatcherMaxBackoff caps the delay between polls after
+// consecutive errors.
+func WithDisruptionWatcherMaxBackoff(maxBackoff time.Duration) DisruptionWatcherOption {
+	return func(w *DisruptionWatcher) {
+		w.maxBackoff = maxBackoff
+	}
+}
+
+func WithDisruptionWatcherErrorHandler(handler func(error)) DisruptionWatcherOption {
+	return func(w *DisruptionWatcher) {
+		w.errorHandler = handler
+	}
+}
+
+// WithTerminalBulletins also watches the bulletins posted to terminals.
+func WithTerminalBulletins() DisruptionWatcherOption {
+	return func(w *DisruptionWatcher) {
+		w.terminalBulletins = true
+	}
+}
+
+// WithInitialDisruptions compares the first poll against previously stored
+// disruptions, such as an earlier result of Disruptions, so nothing is missed
+// or repeated across restarts.
+func WithInitialDisruptions(disruptions []RouteDisruption) DisruptionWatcherOption {
+	return func(w *DisruptionWatcher) {
+		for _, disruption := range disruptions {
+			w.disruptions[disruption.BulletinID] = disruption
+		}
+		w.hasBaseline = true
+	}
+}
+
+// WithInitialTerminalDisruptions is WithInitialDisruptions for terminal
+// bulletins, such as an earlier result of TerminalDisruptions.
+func WithInitialTerminalDisruptions(disruptions []TerminalDisruption) DisruptionWatcherOption {
+	return func(w *DisruptionWatcher) {
+		for _, disruption := range disruptions {
+			w.terminals[disruption.key()] = disruption
+		}
+		w.hasBaseline = true
+	}
+}
+
+// Events returns the channel events are delivered on. It is closed when Run
+// returns.
+func (w *DisruptionWatcher) Events() <-chan DisruptionEvent {
+	return w.events
+}
+
+// Disruptions returns the bulletins currently posted, ordered by BulletinID.
+func (w *DisruptionWatcher) Disruptions() []RouteDisruption {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	disruptions := make([]RouteDisruption, 0, len(w.disruptions))
+	for _, disruption := range w.disruptions {
+		disruptions = append(disruptions, disruption)
+	}
+
+	sort.Slice(disruptions, func(i, j int) bool {
+		return disruptions[i].BulletinID < disruptions[j].BulletinID
+	})
+
+	return disruptions
+}
+
+// TerminalDisruptions returns the terminal bulletins currently posted,
+// ordered by terminal and title.
+func (w *DisruptionWatcher) TerminalDisruptions() []TerminalDisruption {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	disruptions := make([]TerminalDisruption, 0, len(w.terminals))
+	for _, disruption := range w.terminals {
+		disruptions = append(disruptions, disruption)
+	}
+
+	sort.Slice(disruptions, func(i, j int) bool {
+		return lessTerminalDisruption(disruptions[i], disruptions[j])
+	})
+
+	return disruptions
+}
+
+func lessTerminalDisruption(a, b TerminalDisruption) bool {
+	if a.TerminalID != b.TerminalID {
+		return a.TerminalID < b.TerminalID
+	}
+	return a.BulletinTitle < b.BulletinTitle
+}
+
+// Run polls until ctx is cancelled.
+func (w *DisruptionWatcher) Run(ctx context.Context) error {
+	defer close(w.events)
+
+	failures := 0
+	for {
+		delay := w.interval
+		if err := w.poll(ctx); err != nil {
+			if w.errorHandler != nil {
+				w.errorHandler(err)
+			}
+
+			failures++
+			delay = backoff(w.interval, w.maxBackoff, failures)
+		} else {
+			failures = 0
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// routeDisruptions merges the disruptions posted to each route by BulletinID.
+func routeDisruptions(routes []RouteSchedule) map[int]RouteDisruption {
+	disruptions := make(map[int]RouteDisruption)
+	for _, route := range routes {
+		for _, disruption := range route.ServiceDisruptions {
+			merged, ok := disruptions[disruption.BulletinID]
+			if !ok {
+				merged = RouteDisruption{ServiceDisruption: disruption}
+			}
+
+			if !slices.Contains(merged.RouteIDs, route.RouteID) {
+				merged.RouteIDs = append(merged.RouteIDs, route.RouteID)
+				sort.Ints(merged.RouteIDs)
+			}
+
+			disruptions[disruption.BulletinID] = merged
+		}
+	}
+
+	return disruptions
+}
+
+// terminalDisruptions flattens the bulletins posted to each terminal.
+func terminalDisruptions(terminals []TerminalBulletin) map[terminalDisruptionKey]TerminalDisruption {
+	disruptions := make(map[terminalDisruptionKey]TerminalDisruption)
+	for _, terminal := range terminals {
+		for _, bulletin := range terminal.Bulletins {
+			disruption := TerminalDisruption{
+				Bulletin:     bulletin,
+				TerminalID:   terminal.TerminalID,
+				TerminalName: terminal.TerminalName,
+			}
+			disruptions[disruption.key()] = disruption
+		}
+	}
+
+	return disruptions
+}
+
+// poll delivers the changes since the last poll. The watcher's state is only
+// updated as each event is delivered, so if ctx is cancelled part way the
+// undelivered changes are reported again by the next poll, or by a watcher
+// seeded with Disruptions and TerminalDisruptions.
+func (w *DisruptionWatcher) poll(ctx context.Context) error {
+	routes, err := w.getRouteSchedules()
+	if err != nil {
+		return err
+	}
+	current := routeDisruptions(routes)
+
+	currentTerminals := make(map[terminalDisruptionKey]TerminalDisruption)
+	if w.getTerminalBulletins != nil {
+		terminals, err := w.getTerminalBulletins()
+		if err != nil {
+			return err
+		}
+		currentTerminals = terminalDisruptions(terminals)
+	}
+
+	w.mu.Lock()
+	if !w.hasBaseline {
+		w.disruptions, w.terminals, w.hasBaseline = current, currentTerminals, true
+		w.mu.Unlock()
+		return nil
+	}
+	events := append(w.routeEvents(current), w.terminalEvents(currentTerminals)...)
+	w.mu.Unlock()
+
+	for _, event := range events {
+		select {
+		case w.events <- event:
+			w.apply(event)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// routeEvents compares the route disruptions against the watcher's, ordered
+// by BulletinID. w.mu must be held.
+func (w *DisruptionWatcher) routeEvents(current map[int]RouteDisruption) []DisruptionEvent {
+	var events []DisruptionEvent
+	for bulletinID, disruption := range current {
+		previous, ok := w.disruptions[bulletinID]
+		switch {
+		case !ok:
+			events = append(events, newDisruptionEvent(DisruptionAdded, disruption, nil))
+		case !previous.equal(disruption):
+			events = append(events, newDisruptionEvent(DisruptionUpdated, disruption, &previous))
+		}
+	}
+
+	for bulletinID, previous := range w.disruptions {
+		if _, ok := current[bulletinID]; !ok {
+			events = append(events, newDisruptionEvent(DisruptionRemoved, previous, nil))
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Disruption.BulletinID < events[j].Disruption.BulletinID
+	})
+
+	return events
+}
+
+// terminalEvents compares the terminal bulletins against the watcher's,
+// ordered by terminal and title. w.mu must be held.
+func (w *DisruptionWatcher) terminalEvents(current map[terminalDisruptionKey]TerminalDisruption) []DisruptionEvent {
+	var events []DisruptionEvent
+	for key, disruption := range current {
+		previous, ok := w.terminals[key]
+		switch {
+		case !ok:
+			events = append(events, newTerminalDisruptionEvent(DisruptionAdded, disruption, nil))
+		case previous != disruption:
+			events = append(events, newTerminalDisruptionEvent(DisruptionUpdated, disruption, &previous))
+		}
+	}
+
+	for key, previous := range w.terminals {
+		if _, ok := current[key]; !ok {
+			events = append(events, newTerminalDisruptionEvent(DisruptionRemoved, previous, nil))
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return lessTerminalDisruption(*events[i].Terminal, *events[j].Terminal)
+	})
+
+	return events
+}
+
+// apply records a delivered event in the watcher's state.
+func (w *DisruptionWatcher) apply(event DisruptionEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if event.Terminal != nil {
+		if event.Type == DisruptionRemoved {
+			delete(w.terminals, event.Terminal.key())
+		} else {
+			w.terminals[event.Terminal.key()] = *event.Terminal
+		}
+		return
+	}
+
+	if event.Type == DisruptionRemoved {
+		delete(w.disruptions, event.Disruption.BulletinID)
+	} else {
+		w.disruptions[event.Disruption.BulletinID] = event.Disruption
+	}
+}
+
+func newDisruptionEvent(eventType DisruptionEventType, disruption RouteDisruption, previous *RouteDisruption) DisruptionEvent {
+	event := DisruptionEvent{
+		Type:       eventType,
+		Disruption: disruption,
+		Previous:   previous,
+	}
+
+	if published, err := disruption.PublishTime(); err == nil {
+		event.Published = published
+	}
+
+	return event
+}
+
+func newTerminalDisruptionEvent(eventType DisruptionEventType, disruption TerminalDisruption, previous *TerminalDisruption) DisruptionEvent {
+	event := DisruptionEvent{
+		Type:             eventType,
+		Terminal:         &disruption,
+		PreviousTerminal: previous,
+	}
+
+	if published, err := disruption.LastUpdated(); err == nil {
+		event.Published = published
+	}
+
+	return event
+}
@@ -0,0 +1,175 @@
+package ferries
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDisruptionWatcherPoll(t *testing.T) {
+	published := time.Date(2024, 6, 1, 6, 0, 0, 0, time.UTC)
+
+	cancelled := ServiceDisruption{BulletinID: 1, PublishDate: wsdotTime(published), DisruptionDescription: "5:30 am sailing cancelled"}
+	edited := cancelled
+	edited.DisruptionDescription = "5:30 and 6:20 am sailings cancelled"
+	tide := ServiceDisruption{BulletinID: 2, PublishDate: wsdotTime(published), DisruptionDescription: "Low tide"}
+
+	polls := [][]RouteSchedule{
+		{{RouteID: 5, ServiceDisruptions: []ServiceDisruption{cancelled}}},
+		{{RouteID: 5, ServiceDisruptions: []ServiceDisruption{edited}}, {RouteID: 6, ServiceDisruptions: []ServiceDisruption{tide}}},
+		{{RouteID: 6, ServiceDisruptions: []ServiceDisruption{tide}}},
+	}
+
+	tests := []struct {
+		name string
+		want []DisruptionEventType
+		ids  []int
+	}{
+		{name: "Baseline", want: nil},
+		{name: "Edited and added", want: []DisruptionEventType{DisruptionUpdated, DisruptionAdded}, ids: []int{1, 2}},
+		{name: "Removed", want: []DisruptionEventType{DisruptionRemoved}, ids: []int{1}},
+	}
+
+	poll := 0
+	watcher := &DisruptionWatcher{
+		getRouteSchedules: func() ([]RouteSchedule, error) { return polls[poll], nil },
+		events:            make(chan DisruptionEvent, 32),
+		disruptions:       make(map[int]RouteDisruption),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := watcher.poll(context.Background()); err != nil {
+				t.Fatalf("poll() error = %v", err)
+			}
+			poll++
+
+			var got []DisruptionEvent
+			for len(watcher.events) > 0 {
+				got = append(got, <-watcher.events)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("poll() events = %d, want %d", len(got), len(tt.want))
+			}
+
+			for i, event := range got {
+				if event.Type != tt.want[i] || event.Disruption.BulletinID != tt.ids[i] {
+					t.Errorf("poll() event %d = %v %d, want %v %d", i, event.Type, event.Disruption.BulletinID, tt.want[i], tt.ids[i])
+				}
+				if event.Published == nil || !event.Published.Equal(published) {
+					t.Errorf("poll() event %d published = %v, want %v", i, event.Published, published)
+				}
+			}
+		})
+	}
+}
+
+func TestDisruptionWatcherTerminalBulletins(t *testing.T) {
+	updated := time.Date(2024, 6, 1, 6, 0, 0, 0, time.UTC)
+
+	parking := Bulletin{BulletinTitle: "Parking", BulletinText: "Lot full", BulletinLastUpdated: wsdotTime(updated)}
+	edited := parking
+	edited.BulletinText = "Lot reopened"
+	elevator := Bulletin{BulletinTitle: "Elevator", BulletinText: "Out of service", BulletinLastUpdated: wsdotTime(updated)}
+
+	polls := [][]TerminalBulletin{
+		{{TerminalID: 3, Bulletins: []Bulletin{parking}}},
+		{{TerminalID: 3, Bulletins: []Bulletin{edited}}, {TerminalID: 7, Bulletins: []Bulletin{elevator}}},
+		{{TerminalID: 7, Bulletins: []Bulletin{elevator}}},
+	}
+
+	tests := []struct {
+		name      string
+		want      []DisruptionEventType
+		terminals []int
+	}{
+		{name: "Baseline", want: nil},
+		{name: "Edited and added", want: []DisruptionEventType{DisruptionUpdated, DisruptionAdded}, terminals: []int{3, 7}},
+		{name: "Removed", want: []DisruptionEventType{DisruptionRemoved}, terminals: []int{3}},
+	}
+
+	poll := 0
+	watcher := &DisruptionWatcher{
+		getRouteSchedules:    func() ([]RouteSchedule, error) { return nil, nil },
+		getTerminalBulletins: func() ([]TerminalBulletin, error) { return polls[poll], nil },
+		events:               make(chan DisruptionEvent, 32),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := watcher.poll(context.Background()); err != nil {
+				t.Fatalf("poll() error = %v", err)
+			}
+			poll++
+
+			var got []DisruptionEvent
+			for len(watcher.events) > 0 {
+				got = append(got, <-watcher.events)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("poll() events = %d, want %d", len(got), len(tt.want))
+			}
+
+			for i, event := range got {
+				if event.Terminal == nil {
+					t.Fatalf("poll() event %d has no terminal bulletin", i)
+				}
+				if event.Type != tt.want[i] || event.Terminal.TerminalID != tt.terminals[i] {
+					t.Errorf("poll() event %d = %v %d, want %v %d", i, event.Type, event.Terminal.TerminalID, tt.want[i], tt.terminals[i])
+				}
+				if event.Published == nil || !event.Published.Equal(updated) {
+					t.Errorf("poll() event %d published = %v, want %v", i, event.Published, updated)
+				}
+			}
+		})
+	}
+
+	if got := watcher.TerminalDisruptions(); len(got) != 1 || got[0].TerminalID != 7 {
+		t.Errorf("TerminalDisruptions() = %+v, want the terminal 7 bulletin", got)
+	}
+}
+
+func TestDisruptionWatcherPollCancelled(t *testing.T) {
+	first := ServiceDisruption{BulletinID: 1, DisruptionDescription: "5:30 am sailing cancelled"}
+	second := ServiceDisruption{BulletinID: 2, DisruptionDescription: "Low tide"}
+
+	watcher := &DisruptionWatcher{
+		getRouteSchedules: func() ([]RouteSchedule, error) {
+			return []RouteSchedule{{RouteID: 5, ServiceDisruptions: []ServiceDisruption{first, second}}}, nil
+		},
+		// unbuffered, so the second event is never delivered
+		events:      make(chan DisruptionEvent),
+		disruptions: make(map[int]RouteDisruption),
+		hasBaseline: true,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watcher.poll(ctx) }()
+
+	if event := <-watcher.events; event.Disruption.BulletinID != 1 {
+		t.Fatalf("poll() first event = %d, want 1", event.Disruption.BulletinID)
+	}
+	for len(watcher.Disruptions()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("poll() error = %v", err)
+	}
+
+	// the undelivered bulletin is not recorded, so the next poll reports it
+	if got := watcher.Disruptions(); len(got) != 1 || got[0].BulletinID != 1 {
+		t.Fatalf("Disruptions() = %+v, want bulletin 1 only", got)
+	}
+
+	watcher.events = make(chan DisruptionEvent, 32)
+	if err := watcher.poll(context.Background()); err != nil {
+		t.Fatalf("poll() error = %v", err)
+	}
+	if event := <-watcher.events; event.Type != DisruptionAdded || event.Disruption.BulletinID != 2 {
+		t.Errorf("next poll() event = %v %d, want added 2", event.Type, event.Disruption.BulletinID)
+	}
+}
@@ -0,0 +1,224 @@
+package ferries
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"alpineworks.io/wsdot/internal/geo"
+)
+
+const (
+	// crossings are rarely a straight line and vessels slow down to dock
+	routeFactor       = 1.1
+	dockingAllowance  = 2 * time.Minute
+	minimumSpeedKnots = 1
+
+	minHistorySamples = 3
+	maxHistorySamples = 200
+)
+
+var (
+	ErrNoETA = errors.New("no eta available")
+)
+
+type ETASource int
+
+const (
+	ETASourceWSDOT ETASource = iota
+	ETASourcePosition
+	ETASourceHistory
+	ETASourceCombined
+)
+
+func (s ETASource) String() string {
+	switch s {
+	case ETASourceWSDOT:
+		return "wsdot"
+	case ETASourcePosition:
+		return "position"
+	case ETASourceHistory:
+		return "history"
+	case ETASourceCombined:
+		return "combined"
+	default:
+		return "unknown"
+	}
+}
+
+// ETAEstimate is a predicted arrival with the window it is expected to fall
+// within.
+type ETAEstimate struct {
+	ETA      time.Time
+	Earliest time.Time
+	Latest   time.Time
+	Source   ETASource
+}
+
+type crossingKey struct {
+	departingTerminalID int
+	arrivingTerminalID  int
+}
+
+// ETAEstimator predicts arrival times from a vessel's position, speed and
+// heading relative to its arriving terminal, and from historical crossing
+// durations when enough have been recorded. It falls back to the ETA reported
+// by WSDOT when it cannot produce its own.
+type ETAEstimator struct {
+	terminals map[int]geo.Point
+
+	mu        sync.RWMutex
+	crossings map[crossingKey][]time.Duration
+}
+
+func NewETAEstimator(terminals []TerminalLocation) *ETAEstimator {
+	estimator := &ETAEstimator{
+		terminals: make(map[int]geo.Point, len(terminals)),
+		crossings: make(map[crossingKey][]time.Duration),
+	}
+
+	for _, terminal := range terminals {
+		estimator.terminals[terminal.TerminalID] = geo.Point{Latitude: terminal.Latitude, Longitude: terminal.Longitude}
+	}
+
+	return estimator
+}
+
+// AddCrossing records how long a crossing between two terminals took, dock to
+// dock. Only the most recent crossings per terminal pair are kept.
+func (e *ETAEstimator) AddCrossing(departingTerminalID, arrivingTerminalID int, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := crossingKey{departingTerminalID: departingTerminalID, arrivingTerminalID: arrivingTerminalID}
+	crossings := append(e.crossings[key], duration)
+	if len(crossings) > maxHistorySamples {
+		crossings = crossings[len(crossings)-maxHistorySamples:]
+	}
+	e.crossings[key] = crossings
+}
+
+// RecordArrival adds the crossing described by a VesselEventArrived to the
+// history. Other events are ignored.
+func (e *ETAEstimator) RecordArrival(event VesselEvent) {
+	if event.Type != VesselEventArrived || event.LeftDock == nil {
+		return
+	}
+
+	e.AddCrossing(event.DepartingTerminalID, event.ArrivingTerminalID, event.Time.Sub(*event.LeftDock))
+}
+
+// Estimate predicts when the vessel will reach its arriving terminal.
+func (e *ETAEstimator) Estimate(location VesselLocation, now time.Time) (*ETAEstimate, error) {
+	position := e.positionEstimate(location, now)
+	history := e.historyEstimate(location, now)
+
+	switch {
+	case position != nil && history != nil:
+		return combineEstimates(*position, *history), nil
+	case position != nil:
+		return position, nil
+	case history != nil:
+		return history, nil
+	}
+
+	if eta := parseOptionalWSDOTTime(location.Eta); eta != nil {
+		return &ETAEstimate{ETA: *eta, Earliest: *eta, Latest: *eta, Source: ETASourceWSDOT}, nil
+	}
+
+	return nil, ErrNoETA
+}
+
+func (e *ETAEstimator) positionEstimate(location VesselLocation, now time.Time) *ETAEstimate {
+	if location.AtDock || location.Speed < minimumSpeedKnots {
+		return nil
+	}
+
+	terminal, ok := e.terminals[location.ArrivingTerminalID]
+	if !ok {
+		return nil
+	}
+
+	vessel := geo.Point{Latitude: location.Latitude, Longitude: location.Longitude}
+	distance := geo.Distance(vessel, terminal)
+	metersPerSecond := location.Speed * geo.MetersPerNauticalMile / 3600
+
+	direct := time.Duration(distance / metersPerSecond * float64(time.Second))
+	expected := time.Duration(float64(direct)*routeFactor) + dockingAllowance
+
+	// a vessel not pointed at its terminal is maneuvering or following a
+	// dog-leg course, so widen the window the further off it is
+	offCourse := geo.AngleBetween(geo.Bearing(vessel, terminal), float64(location.Heading)) / 180
+	latest := time.Duration(float64(expected) * (1.3 + offCourse))
+
+	return &ETAEstimate{
+		ETA:      now.Add(expected),
+		Earliest: now.Add(direct),
+		Latest:   now.Add(latest),
+		Source:   ETASourcePosition,
+	}
+}
+
+func (e *ETAEstimator) historyEstimate(location VesselLocation, now time.Time) *ETAEstimate {
+	if location.AtDock {
+		return nil
+	}
+
+	leftDock := parseOptionalWSDOTTime(location.LeftDock)
+	if leftDock == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	crossings := append([]time.Duration(nil), e.crossings[crossingKey{departingTerminalID: location.DepartingTerminalID, arrivingTerminalID: location.ArrivingTerminalID}]...)
+	e.mu.RUnlock()
+
+	if len(crossings) < minHistorySamples {
+		return nil
+	}
+
+	sort.Slice(crossings, func(i, j int) bool { return crossings[i] < crossings[j] })
+	percentile := func(p float64) time.Duration {
+		return crossings[int(p*float64(len(crossings)-1))]
+	}
+
+	clamp := func(t time.Time) time.Time {
+		if t.Before(now) {
+			return now
+		}
+		return t
+	}
+
+	return &ETAEstimate{
+		ETA:      clamp(leftDock.Add(percentile(0.5))),
+		Earliest: clamp(leftDock.Add(percentile(0.1))),
+		Latest:   clamp(leftDock.Add(percentile(0.9))),
+		Source:   ETASourceHistory,
+	}
+}
+
+// combineEstimates weights each estimate by the inverse of its window width,
+// so the more certain estimate dominates.
+func combineEstimates(a, b ETAEstimate) *ETAEstimate {
+	weight := func(estimate ETAEstimate) float64 {
+		return 1 / (estimate.Latest.Sub(estimate.Earliest).Minutes() + 1)
+	}
+	wa, wb := weight(a), weight(b)
+
+	blend := func(x, y time.Time) time.Time {
+		offset := float64(y.Sub(x)) * wb / (wa + wb)
+		return x.Add(time.Duration(offset))
+	}
+
+	return &ETAEstimate{
+		ETA:      blend(a.ETA, b.ETA),
+		Earliest: blend(a.Earliest, b.Earliest),
+		Latest:   blend(a.Latest, b.Latest),
+		Source:   ETASourceCombined,
+	}
+}
@@ -0,0 +1,71 @@
+package ferries
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestETAEstimator(t *testing.T) {
+	terminals := []TerminalLocation{
+		{TerminalID: 3, TerminalName: "Bainbridge Island", Latitude: 47.622339, Longitude: -122.509617},
+		{TerminalID: 7, TerminalName: "Seattle", Latitude: 47.602501, Longitude: -122.340472},
+	}
+	now := time.Date(2024, 3, 1, 8, 20, 0, 0, time.UTC)
+
+	// halfway across Elliott Bay, heading for Bainbridge at 17 knots
+	underway := VesselLocation{
+		DepartingTerminalID: 7,
+		ArrivingTerminalID:  3,
+		Latitude:            47.6125,
+		Longitude:           -122.4250,
+		Speed:               17,
+		Heading:             280,
+		LeftDock:            wsdotTime(now.Add(-15 * time.Minute)),
+	}
+
+	estimator := NewETAEstimator(terminals)
+
+	estimate, err := estimator.Estimate(underway, now)
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if estimate.Source != ETASourcePosition {
+		t.Errorf("Estimate() source = %s, want %s", estimate.Source, ETASourcePosition)
+	}
+	if remaining := estimate.ETA.Sub(now); remaining < 12*time.Minute || remaining > 18*time.Minute {
+		t.Errorf("Estimate() remaining = %v, want about 15m", remaining)
+	}
+	if estimate.Earliest.After(estimate.ETA) || estimate.Latest.Before(estimate.ETA) {
+		t.Errorf("Estimate() window %v - %v does not contain %v", estimate.Earliest, estimate.Latest, estimate.ETA)
+	}
+
+	for _, minutes := range []int{33, 35, 36} {
+		estimator.AddCrossing(7, 3, time.Duration(minutes)*time.Minute)
+	}
+
+	estimate, err = estimator.Estimate(underway, now)
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if estimate.Source != ETASourceCombined {
+		t.Errorf("Estimate() source = %s, want %s", estimate.Source, ETASourceCombined)
+	}
+
+	docked := underway
+	docked.AtDock = true
+	docked.Eta = wsdotTime(now.Add(40 * time.Minute))
+
+	estimate, err = estimator.Estimate(docked, now)
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if estimate.Source != ETASourceWSDOT || !estimate.ETA.Equal(now.Add(40*time.Minute)) {
+		t.Errorf("Estimate() = %+v, want the WSDOT eta", estimate)
+	}
+
+	docked.Eta = ""
+	if _, err := estimator.Estimate(docked, now); !errors.Is(err, ErrNoETA) {
+		t.Errorf("Estimate() error = %v, want %v", err, ErrNoETA)
+	}
+}
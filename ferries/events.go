@@ -0,0 +1,228 @@
+package ferries
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	DefaultLateThreshold = 5 * time.Minute
+)
+
+type VesselEventType int
+
+const (
+	VesselEventDeparted VesselEventType = iota + 1
+	VesselEventArrived
+	VesselEventOutOfService
+	VesselEventInService
+	VesselEventRouteChanged
+	VesselEventLate
+)
+
+func (t VesselEventType) String() string {
+	switch t {
+	case VesselEventDeparted:
+		return "departed"
+	case VesselEventArrived:
+		return "arrived"
+	case VesselEventOutOfService:
+		return "out of service"
+	case VesselEventInService:
+		return "in service"
+	case VesselEventRouteChanged:
+		return "route changed"
+	case VesselEventLate:
+		return "late"
+	default:
+		return "unknown"
+	}
+}
+
+type VesselEvent struct {
+	Type                  VesselEventType
+	VesselID              int
+	VesselName            string
+	DepartingTerminalID   int
+	DepartingTerminalName string
+	ArrivingTerminalID    int
+	ArrivingTerminalName  string
+	Routes                []string
+	PreviousRoutes        []string
+	ScheduledDeparture    *time.Time
+	LeftDock              *time.Time
+	// DelayMinutes is how far behind ScheduledDeparture the vessel left, or
+	// for VesselEventLate how long it has been held at the dock.
+	DelayMinutes int
+	Time         time.Time
+	Location     VesselLocation
+}
+
+// VesselEventEngine derives departures, arrivals, service changes, route
+// changes and late departures from consecutive locations of each vessel.
+type VesselEventEngine struct {
+	lateThreshold time.Duration
+	now           func() time.Time
+
+	previous     map[int]VesselLocation
+	lateReported map[int]string
+
+	events chan VesselEvent
+}
+
+type VesselEventEngineOption func(*VesselEventEngine)
+
+func NewVesselEventEngine(options ...VesselEventEngineOption) *VesselEventEngine {
+	engine := &VesselEventEngine{
+		lateThreshold: DefaultLateThreshold,
+		now:           time.Now,
+		previous:      make(map[int]VesselLocation),
+		lateReported:  make(map[int]string),
+		events:        make(chan VesselEvent, 32),
+	}
+
+	for _, option := range options {
+		option(engine)
+	}
+
+	return engine
+}
+
+// WithLateThreshold sets how long past ScheduledDeparture a docked vessel
+// must be before a VesselEventLate is emitted.
+func WithLateThreshold(lateThreshold time.Duration) VesselEventEngineOption {
+	return func(e *VesselEventEngine) {
+		e.lateThreshold = lateThreshold
+	}
+}
+
+// Events returns the channel Run delivers events on. It is closed when Run
+// returns.
+func (e *VesselEventEngine) Events() <-chan VesselEvent {
+	return e.events
+}
+
+// Run processes locations from updates, typically a VesselWatcher's Updates
+// channel, until ctx is cancelled or updates is closed.
+func (e *VesselEventEngine) Run(ctx context.Context, updates <-chan VesselLocation) error {
+	defer close(e.events)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case location, ok := <-updates:
+			if !ok {
+				return nil
+			}
+
+			for _, event := range e.Process(location) {
+				select {
+				case e.events <- event:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+}
+
+// Process records the location and returns the events it implies. It is not
+// safe for concurrent use; Run calls it from a single goroutine.
+func (e *VesselEventEngine) Process(location VesselLocation) []VesselEvent {
+	previous, seen := e.previous[location.VesselID]
+	e.previous[location.VesselID] = location
+
+	observed := e.now()
+	if t := parseOptionalWSDOTTime(location.TimeStamp); t != nil {
+		observed = *t
+	}
+
+	newEvent := func(eventType VesselEventType, from VesselLocation) VesselEvent {
+		return VesselEvent{
+			Type:                  eventType,
+			VesselID:              location.VesselID,
+			VesselName:            location.VesselName,
+			DepartingTerminalID:   from.DepartingTerminalID,
+			DepartingTerminalName: from.DepartingTerminalName,
+			ArrivingTerminalID:    from.ArrivingTerminalID,
+			ArrivingTerminalName:  from.ArrivingTerminalName,
+			Routes:                location.OpRouteAbbrev,
+			ScheduledDeparture:    parseOptionalWSDOTTime(from.ScheduledDeparture),
+			LeftDock:              parseOptionalWSDOTTime(location.LeftDock),
+			Time:                  observed,
+			Location:              location,
+		}
+	}
+
+	var events []VesselEvent
+
+	if seen {
+		if previous.InService && !location.InService {
+			events = append(events, newEvent(VesselEventOutOfService, location))
+		}
+		if !previous.InService && location.InService {
+			events = append(events, newEvent(VesselEventInService, location))
+		}
+
+		if previous.AtDock && !location.AtDock {
+			event := newEvent(VesselEventDeparted, location)
+			if event.ScheduledDeparture != nil {
+				departed := observed
+				if event.LeftDock != nil {
+					departed = *event.LeftDock
+				}
+				event.DelayMinutes = delayMinutes(departed.Sub(*event.ScheduledDeparture))
+			}
+			events = append(events, event)
+		}
+
+		if !previous.AtDock && location.AtDock {
+			// the previous location still names the terminal the vessel was
+			// heading for
+			event := newEvent(VesselEventArrived, previous)
+			event.LeftDock = parseOptionalWSDOTTime(previous.LeftDock)
+			events = append(events, event)
+		}
+
+		if len(previous.OpRouteAbbrev) > 0 && len(location.OpRouteAbbrev) > 0 && !slices.Equal(previous.OpRouteAbbrev, location.OpRouteAbbrev) {
+			event := newEvent(VesselEventRouteChanged, location)
+			event.PreviousRoutes = previous.OpRouteAbbrev
+			events = append(events, event)
+		}
+	}
+
+	if location.AtDock && location.InService && location.ScheduledDeparture != "" && e.lateReported[location.VesselID] != location.ScheduledDeparture {
+		if scheduled := parseOptionalWSDOTTime(location.ScheduledDeparture); scheduled != nil {
+			if late := observed.Sub(*scheduled); late >= e.lateThreshold {
+				event := newEvent(VesselEventLate, location)
+				event.DelayMinutes = delayMinutes(late)
+				events = append(events, event)
+				e.lateReported[location.VesselID] = location.ScheduledDeparture
+			}
+		}
+	}
+
+	return events
+}
+
+func delayMinutes(delay time.Duration) int {
+	return int(math.Round(delay.Minutes()))
+}
+
+// parseOptionalWSDOTTime parses a /Date(...)/ string, returning nil when it
+// is empty or malformed.
+func parseOptionalWSDOTTime(wsdotTime string) *time.Time {
+	if wsdotTime == "" {
+		return nil
+	}
+
+	t, err := wsdotTimeStringToTime(wsdotTime)
+	if err != nil {
+		return nil
+	}
+
+	return t
+}
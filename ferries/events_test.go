@@ -0,0 +1,120 @@
+package ferries
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func wsdotTime(t time.Time) string {
+	return fmt.Sprintf("/Date(%d-0700)/", t.UnixMilli())
+}
+
+func TestVesselEventEngine(t *testing.T) {
+	scheduled := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	docked := VesselLocation{
+		VesselID:              2,
+		VesselName:            "Chimacum",
+		DepartingTerminalID:   7,
+		DepartingTerminalName: "Seattle",
+		ArrivingTerminalID:    3,
+		ArrivingTerminalName:  "Bainbridge Island",
+		InService:             true,
+		AtDock:                true,
+		ScheduledDeparture:    wsdotTime(scheduled),
+		OpRouteAbbrev:         []string{"sea-bi"},
+	}
+
+	tests := []struct {
+		name     string
+		location func() VesselLocation
+		want     []VesselEventType
+		delay    int
+	}{
+		{
+			name: "Docked on time",
+			location: func() VesselLocation {
+				l := docked
+				l.TimeStamp = wsdotTime(scheduled.Add(-2 * time.Minute))
+				return l
+			},
+		},
+		{
+			name: "Held at the dock",
+			location: func() VesselLocation {
+				l := docked
+				l.TimeStamp = wsdotTime(scheduled.Add(6 * time.Minute))
+				return l
+			},
+			want:  []VesselEventType{VesselEventLate},
+			delay: 6,
+		},
+		{
+			name: "Still held, already reported",
+			location: func() VesselLocation {
+				l := docked
+				l.TimeStamp = wsdotTime(scheduled.Add(8 * time.Minute))
+				return l
+			},
+		},
+		{
+			name: "Left the dock",
+			location: func() VesselLocation {
+				l := docked
+				l.AtDock = false
+				l.LeftDock = wsdotTime(scheduled.Add(9 * time.Minute))
+				l.TimeStamp = wsdotTime(scheduled.Add(10 * time.Minute))
+				return l
+			},
+			want:  []VesselEventType{VesselEventDeparted},
+			delay: 9,
+		},
+		{
+			name: "Arrived and reassigned",
+			location: func() VesselLocation {
+				l := docked
+				l.DepartingTerminalID, l.DepartingTerminalName = 3, "Bainbridge Island"
+				l.ArrivingTerminalID, l.ArrivingTerminalName = 7, "Seattle"
+				l.ScheduledDeparture = wsdotTime(scheduled.Add(50 * time.Minute))
+				l.OpRouteAbbrev = []string{"sea-br"}
+				l.TimeStamp = wsdotTime(scheduled.Add(45 * time.Minute))
+				return l
+			},
+			want: []VesselEventType{VesselEventArrived, VesselEventRouteChanged},
+		},
+		{
+			name: "Taken out of service",
+			location: func() VesselLocation {
+				l := docked
+				l.InService = false
+				l.OpRouteAbbrev = []string{"sea-br"}
+				l.TimeStamp = wsdotTime(scheduled.Add(2 * time.Hour))
+				return l
+			},
+			want: []VesselEventType{VesselEventOutOfService},
+		},
+	}
+
+	engine := NewVesselEventEngine()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := engine.Process(tt.location())
+			if len(events) != len(tt.want) {
+				t.Fatalf("Process() returned %d events, want %d: %+v", len(events), len(tt.want), events)
+			}
+
+			for i, event := range events {
+				if event.Type != tt.want[i] {
+					t.Errorf("event %d = %s, want %s", i, event.Type, tt.want[i])
+				}
+				if (event.Type == VesselEventLate || event.Type == VesselEventDeparted) && event.DelayMinutes != tt.delay {
+					t.Errorf("event %d DelayMinutes = %d, want %d", i, event.DelayMinutes, tt.delay)
+				}
+				if event.Type == VesselEventArrived && event.ArrivingTerminalID != 3 {
+					t.Errorf("arrived at terminal %d, want 3", event.ArrivingTerminalID)
+				}
+			}
+		})
+	}
+}
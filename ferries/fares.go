@@ -0,0 +1,57 @@
+package ferries
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"alpineworks.io/wsdot"
+)
+
+const (
+	getFareLineItemsBasicAsJsonURL = "https://www.wsdot.wa.gov/Ferries/API/Fares/rest/farelineitemsbasic/%s/%d/%d/%t"
+
+	fareDateLayout = "2006-01-02"
+)
+
+type FareLineItem struct {
+	FareLineItemID       int     `json:"FareLineItemID"`
+	FareLineItem         string  `json:"FareLineItem"`
+	Category             string  `json:"Category"`
+	DirectionIndependent bool    `json:"DirectionIndependent"`
+	Amount               float64 `json:"Amount"`
+}
+
+// GetFareLineItemsBasic returns the most popular fares for a trip between
+// two terminals on the given date.
+func (f *FerriesClient) GetFareLineItemsBasic(tripDate time.Time, departingTerminalID, arrivingTerminalID int, roundTrip bool) ([]FareLineItem, error) {
+	url := fmt.Sprintf(getFareLineItemsBasicAsJsonURL, tripDate.In(TimeZone()).Format(fareDateLayout), departingTerminalID, arrivingTerminalID, roundTrip)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	q := req.URL.Query()
+	q.Add(wsdot.ParamFerriesAccessCodeKey, f.wsdot.ApiKey)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.wsdot.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var fares []FareLineItem
+	if err := json.NewDecoder(resp.Body).Decode(&fares); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return fares, nil
+}
@@ -0,0 +1,443 @@
+// Package gtfs exports WSF schedules as a GTFS static feed.
+//
+// The schedule API only publishes one day of sailings at a time, so a feed
+// covers exactly the service days added to the Builder. Each route and
+// service day gets its own service ID, and contingency cancellations of the
+// route on that day are written to calendar_dates.txt.
+package gtfs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+const (
+	DefaultAgencyID   = "WSF"
+	DefaultAgencyName = "Washington State Ferries"
+	DefaultAgencyURL  = "https://wsdot.wa.gov/travel/washington-state-ferries"
+
+	// DefaultCrossingTime is used for sailings without an arriving time on
+	// combos where no sailing has one to estimate from.
+	DefaultCrossingTime = 30 * time.Minute
+
+	RouteTypeFerry = 4
+
+	dateLayout = "20060102"
+)
+
+const (
+	WheelchairUnknown = iota
+	WheelchairAccessible
+	WheelchairInaccessible
+)
+
+const (
+	ExceptionAdded   = 1
+	ExceptionRemoved = 2
+)
+
+type Agency struct {
+	ID       string
+	Name     string
+	URL      string
+	Timezone string
+	Lang     string
+	Phone    string
+}
+
+type Stop struct {
+	ID        string
+	Code      string
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+type Route struct {
+	ID        string
+	AgencyID  string
+	ShortName string
+	LongName  string
+	Desc      string
+	Type      int
+}
+
+type Trip struct {
+	ID                   string
+	RouteID              string
+	ServiceID            string
+	Headsign             string
+	WheelchairAccessible int
+}
+
+// StopTime times are offsets from noon minus twelve hours on the service
+// date, so sailings after midnight are written as 24:30:00 and later.
+type StopTime struct {
+	TripID        string
+	ArrivalTime   time.Duration
+	DepartureTime time.Duration
+	StopID        string
+	StopSequence  int
+	// Timepoint is false when the time was estimated rather than published.
+	Timepoint bool
+}
+
+type Calendar struct {
+	ServiceID string
+	// Days is indexed by time.Weekday.
+	Days      [7]bool
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+type CalendarDate struct {
+	ServiceID     string
+	Date          time.Time
+	ExceptionType int
+}
+
+type FeedInfo struct {
+	PublisherName string
+	PublisherURL  string
+	Lang          string
+	StartDate     time.Time
+	EndDate       time.Time
+}
+
+type Feed struct {
+	Agencies      []Agency
+	Stops         []Stop
+	Routes        []Route
+	Trips         []Trip
+	StopTimes     []StopTime
+	Calendars     []Calendar
+	CalendarDates []CalendarDate
+	FeedInfo      *FeedInfo
+}
+
+// StopID returns the GTFS stop_id used for a terminal.
+func StopID(terminalID int64) string {
+	return strconv.FormatInt(terminalID, 10)
+}
+
+// RouteID returns the GTFS route_id used for a WSF route.
+func RouteID(routeID int) string {
+	return strconv.Itoa(routeID)
+}
+
+// TripID returns the GTFS trip_id of the sailing between two terminals
+// leaving at departure, e.g. "20261019-7-3-0550". It is stable across feeds,
+// so realtime updates can refer to trips by it.
+func TripID(departingTerminalID, arrivingTerminalID int64, departure time.Time) string {
+	local := departure.In(ferries.TimeZone())
+
+	return fmt.Sprintf("%s-%d-%d-%s", local.Format(dateLayout), departingTerminalID, arrivingTerminalID, local.Format("1504"))
+}
+
+// ServiceID returns the GTFS service_id of a route on a service date.
+func ServiceID(routeID int, serviceDate time.Time) string {
+	return fmt.Sprintf("%d-%s", routeID, serviceDate.Format(dateLayout))
+}
+
+// ServiceDayCutoff is the Pacific clock time before which a sailing belongs
+// to the previous day's service.
+const ServiceDayCutoff = ferries.ServiceDayCutoff
+
+// serviceReference is noon minus twelve hours on the service date, the time
+// GTFS stop times are measured from.
+func serviceReference(serviceDate time.Time) time.Time {
+	return time.Date(serviceDate.Year(), serviceDate.Month(), serviceDate.Day(), 12, 0, 0, 0, ferries.TimeZone()).Add(-12 * time.Hour)
+}
+
+// ServiceTime returns the service date a departure belongs to and its offset
+// from that service day, so a sailing at 00:50 is on the previous service
+// date at 24:50:00. Realtime feeds use it to refer to the same trips as the
+// static feed.
+func ServiceTime(departure time.Time) (time.Time, time.Duration) {
+	local := departure.In(ferries.TimeZone())
+
+	serviceDate := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, ferries.TimeZone())
+	if local.Sub(serviceDate) < ServiceDayCutoff {
+		serviceDate = serviceDate.AddDate(0, 0, -1)
+	}
+
+	return serviceDate, departure.Sub(serviceReference(serviceDate))
+}
+
+type day struct {
+	date     time.Time
+	schedule ferries.Schedule
+}
+
+type Builder struct {
+	agency              Agency
+	defaultCrossingTime time.Duration
+
+	terminals []ferries.TerminalLocation
+	routes    []ferries.RouteSchedule
+	days      []day
+}
+
+type BuilderOption func(*Builder)
+
+// NewBuilder starts a feed for the terminals and route schedules, as returned
+// by GetTerminalLocations and GetRouteSchedules.
+func NewBuilder(terminals []ferries.TerminalLocation, routes []ferries.RouteSchedule, options ...BuilderOption) *Builder {
+	builder := &Builder{
+		agency: Agency{
+			ID:       DefaultAgencyID,
+			Name:     DefaultAgencyName,
+			URL:      DefaultAgencyURL,
+			Timezone: "America/Los_Angeles",
+			Lang:     "en",
+		},
+		defaultCrossingTime: DefaultCrossingTime,
+		terminals:           terminals,
+		routes:              routes,
+	}
+
+	for _, option := range options {
+		option(builder)
+	}
+
+	return builder
+}
+
+func WithAgency(agency Agency) BuilderOption {
+	return func(b *Builder) {
+		b.agency = agency
+	}
+}
+
+func WithDefaultCrossingTime(crossingTime time.Duration) BuilderOption {
+	return func(b *Builder) {
+		b.defaultCrossingTime = crossingTime
+	}
+}
+
+// AddSchedule adds the sailings of a route's schedule for one service date,
+// typically the result of GetSchedulesTodayByRouteID. Sailings shared by
+// several routes are only exported once.
+func (b *Builder) AddSchedule(serviceDate time.Time, schedule ferries.Schedule) {
+	local := serviceDate.In(ferries.TimeZone())
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, ferries.TimeZone())
+
+	b.days = append(b.days, day{date: date, schedule: schedule})
+}
+
+type service struct {
+	routeID int
+	date    time.Time
+}
+
+// Build creates the feed and validates it.
+func (b *Builder) Build() (*Feed, error) {
+	feed := &Feed{
+		Agencies: []Agency{b.agency},
+	}
+
+	for _, terminal := range b.terminals {
+		feed.Stops = append(feed.Stops, Stop{
+			ID:        StopID(int64(terminal.TerminalID)),
+			Code:      terminal.TerminalAbbrev,
+			Name:      terminal.TerminalName,
+			Latitude:  terminal.Latitude,
+			Longitude: terminal.Longitude,
+		})
+	}
+
+	routes := make(map[int]Route)
+	for _, route := range b.routes {
+		// contingency sched routes share the RouteID of the regular one
+		if _, ok := routes[route.RouteID]; ok && route.ContingencyOnly {
+			continue
+		}
+
+		routes[route.RouteID] = Route{
+			ID:        RouteID(route.RouteID),
+			AgencyID:  b.agency.ID,
+			ShortName: route.RouteAbbrev,
+			LongName:  route.Description,
+			Desc:      route.SeasonalRouteNotes,
+			Type:      RouteTypeFerry,
+		}
+	}
+
+	services := make(map[string]service)
+	trips := make(map[string]struct{})
+
+	for _, d := range b.days {
+		reference := serviceReference(d.date)
+
+		for _, combo := range d.schedule.TerminalCombos {
+			crossing := typicalCrossing(combo, b.defaultCrossingTime)
+
+			for _, sailing := range combo.Times {
+				if sailing.DepartingTime == nil {
+					continue
+				}
+
+				tripID := TripID(combo.DepartingTerminalID, combo.ArrivingTerminalID, *sailing.DepartingTime)
+				if _, ok := trips[tripID]; ok {
+					continue
+				}
+
+				routeID, ok := b.routeOf(sailing, d.schedule)
+				if !ok {
+					continue
+				}
+				trips[tripID] = struct{}{}
+
+				if _, ok := routes[routeID]; !ok {
+					routes[routeID] = Route{
+						ID:        RouteID(routeID),
+						AgencyID:  b.agency.ID,
+						ShortName: RouteID(routeID),
+						Type:      RouteTypeFerry,
+					}
+				}
+
+				serviceID := ServiceID(routeID, d.date)
+				services[serviceID] = service{routeID: routeID, date: d.date}
+
+				wheelchair := WheelchairInaccessible
+				if sailing.VesselHandicapAccessible {
+					wheelchair = WheelchairAccessible
+				}
+
+				feed.Trips = append(feed.Trips, Trip{
+					ID:                   tripID,
+					RouteID:              RouteID(routeID),
+					ServiceID:            serviceID,
+					Headsign:             combo.ArrivingTerminalName,
+					WheelchairAccessible: wheelchair,
+				})
+
+				departure := sailing.DepartingTime.Sub(reference)
+				arrival, timepoint := departure+crossing, false
+				if sailing.ArrivingTime != nil {
+					arrival, timepoint = sailing.ArrivingTime.Sub(reference), true
+				}
+
+				feed.StopTimes = append(feed.StopTimes,
+					StopTime{
+						TripID:        tripID,
+						ArrivalTime:   departure,
+						DepartureTime: departure,
+						StopID:        StopID(combo.DepartingTerminalID),
+						StopSequence:  1,
+						Timepoint:     true,
+					},
+					StopTime{
+						TripID:        tripID,
+						ArrivalTime:   arrival,
+						DepartureTime: arrival,
+						StopID:        StopID(combo.ArrivingTerminalID),
+						StopSequence:  2,
+						Timepoint:     timepoint,
+					},
+				)
+			}
+		}
+	}
+
+	for _, route := range routes {
+		feed.Routes = append(feed.Routes, route)
+	}
+	sort.Slice(feed.Routes, func(i, j int) bool { return feed.Routes[i].ID < feed.Routes[j].ID })
+
+	for serviceID, s := range services {
+		var calendar Calendar
+		calendar.ServiceID = serviceID
+		calendar.Days[s.date.Weekday()] = true
+		calendar.StartDate, calendar.EndDate = s.date, s.date
+		feed.Calendars = append(feed.Calendars, calendar)
+
+		if b.cancelled(s) {
+			feed.CalendarDates = append(feed.CalendarDates, CalendarDate{
+				ServiceID:     serviceID,
+				Date:          s.date,
+				ExceptionType: ExceptionRemoved,
+			})
+		}
+
+		if feed.FeedInfo == nil {
+			feed.FeedInfo = &FeedInfo{
+				PublisherName: b.agency.Name,
+				PublisherURL:  b.agency.URL,
+				Lang:          b.agency.Lang,
+				StartDate:     s.date,
+				EndDate:       s.date,
+			}
+		}
+		if s.date.Before(feed.FeedInfo.StartDate) {
+			feed.FeedInfo.StartDate = s.date
+		}
+		if s.date.After(feed.FeedInfo.EndDate) {
+			feed.FeedInfo.EndDate = s.date
+		}
+	}
+	sort.Slice(feed.Calendars, func(i, j int) bool { return feed.Calendars[i].ServiceID < feed.Calendars[j].ServiceID })
+	sort.Slice(feed.CalendarDates, func(i, j int) bool { return feed.CalendarDates[i].ServiceID < feed.CalendarDates[j].ServiceID })
+
+	if err := feed.Validate(); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// routeOf picks the route a sailing is exported under, preferring routes the
+// builder has a RouteSchedule for.
+func (b *Builder) routeOf(sailing ferries.Time, schedule ferries.Schedule) (int, bool) {
+	candidates := append(append([]int64(nil), sailing.Routes...), schedule.AllRoutes...)
+
+	for _, candidate := range candidates {
+		for _, route := range b.routes {
+			if int64(route.RouteID) == candidate {
+				return route.RouteID, true
+			}
+		}
+	}
+
+	if len(candidates) > 0 {
+		return int(candidates[0]), true
+	}
+
+	return 0, false
+}
+
+// cancelled reports whether contingency adjustments cancel the route on the
+// service date without a replacement sched route.
+func (b *Builder) cancelled(s service) bool {
+	for _, route := range ferries.ResolveEffectiveSchedules(b.routes, s.date) {
+		if route.RouteID == s.routeID {
+			return route.Cancelled
+		}
+	}
+
+	return false
+}
+
+// typicalCrossing returns the median crossing time of the combo's sailings
+// that have an arriving time, or fallback when none do.
+func typicalCrossing(combo ferries.TerminalCombo, fallback time.Duration) time.Duration {
+	var durations []time.Duration
+	for _, sailing := range combo.Times {
+		if sailing.DepartingTime != nil && sailing.ArrivingTime != nil {
+			durations = append(durations, sailing.ArrivingTime.Sub(*sailing.DepartingTime))
+		}
+	}
+
+	if len(durations) == 0 {
+		return fallback
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	return durations[len(durations)/2]
+}
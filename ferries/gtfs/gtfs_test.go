@@ -0,0 +1,298 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+func local(day, hour, minute int) *time.Time {
+	t := time.Date(2024, 6, day, hour, minute, 0, 0, ferries.TimeZone())
+	return &t
+}
+
+func wsdotDate(t time.Time) string {
+	return fmt.Sprintf("/Date(%d-0700)/", t.UnixMilli())
+}
+
+func testBuilder() *Builder {
+	terminals := []ferries.TerminalLocation{
+		{TerminalID: 7, TerminalName: "Seattle", TerminalAbbrev: "P52", Latitude: 47.602501, Longitude: -122.340472},
+		{TerminalID: 3, TerminalName: "Bainbridge Island", TerminalAbbrev: "BBI", Latitude: 47.622339, Longitude: -122.509617},
+	}
+
+	routes := []ferries.RouteSchedule{
+		{
+			ScheduleID:  100,
+			RouteID:     5,
+			RouteAbbrev: "SEA-BI",
+			Description: "Seattle / Bainbridge Island",
+			ContingencyAdj: []ferries.ContingencyAdjustment{
+				{DateFrom: wsdotDate(*local(2, 0, 0)), DateThru: wsdotDate(*local(2, 23, 59)), AdjType: ferries.AdjTypeCancellation},
+			},
+		},
+	}
+
+	builder := NewBuilder(terminals, routes)
+
+	schedule := ferries.Schedule{
+		ScheduleID: 100,
+		AllRoutes:  []int64{5},
+		TerminalCombos: []ferries.TerminalCombo{
+			{
+				DepartingTerminalID:  7,
+				ArrivingTerminalID:   3,
+				ArrivingTerminalName: "Bainbridge Island",
+				Times: []ferries.Time{
+					{DepartingTime: local(1, 5, 30), ArrivingTime: local(1, 6, 5), VesselHandicapAccessible: true},
+					{DepartingTime: local(1, 12, 0), ArrivingTime: local(1, 12, 35), VesselHandicapAccessible: true},
+					{DepartingTime: local(2, 0, 50)},
+				},
+			},
+		},
+	}
+	builder.AddSchedule(*local(1, 0, 0), schedule)
+
+	cancelled := schedule
+	cancelled.TerminalCombos = []ferries.TerminalCombo{
+		{
+			DepartingTerminalID: 7,
+			ArrivingTerminalID:  3,
+			Times:               []ferries.Time{{DepartingTime: local(2, 5, 30), ArrivingTime: local(2, 6, 5)}},
+		},
+	}
+	builder.AddSchedule(*local(2, 0, 0), cancelled)
+
+	return builder
+}
+
+func TestBuild(t *testing.T) {
+	feed, err := testBuilder().Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if len(feed.Trips) != 4 {
+		t.Fatalf("Build() trips = %d, want 4", len(feed.Trips))
+	}
+
+	if got, want := feed.Trips[0].ID, "20240601-7-3-0530"; got != want {
+		t.Errorf("Build() trip id = %v, want %v", got, want)
+	}
+
+	lateNight := feed.StopTimes[4:6]
+	if got, want := FormatTime(lateNight[0].DepartureTime), "24:50:00"; got != want {
+		t.Errorf("Build() past midnight departure = %v, want %v", got, want)
+	}
+	if got, want := FormatTime(lateNight[1].ArrivalTime), "25:25:00"; got != want {
+		t.Errorf("Build() estimated arrival = %v, want %v", got, want)
+	}
+	if lateNight[1].Timepoint {
+		t.Errorf("Build() estimated arrival timepoint = true, want false")
+	}
+	if got, want := feed.Trips[2].WheelchairAccessible, WheelchairInaccessible; got != want {
+		t.Errorf("Build() wheelchair = %v, want %v", got, want)
+	}
+
+	if len(feed.CalendarDates) != 1 || feed.CalendarDates[0].ServiceID != "5-20240602" || feed.CalendarDates[0].ExceptionType != ExceptionRemoved {
+		t.Errorf("Build() calendar dates = %+v, want 5-20240602 removed", feed.CalendarDates)
+	}
+}
+
+func TestBuildUnknownStop(t *testing.T) {
+	builder := NewBuilder(nil, nil)
+	builder.AddSchedule(*local(1, 0, 0), ferries.Schedule{
+		AllRoutes: []int64{5},
+		TerminalCombos: []ferries.TerminalCombo{
+			{DepartingTerminalID: 7, ArrivingTerminalID: 3, Times: []ferries.Time{{DepartingTime: local(1, 5, 30)}}},
+		},
+	})
+
+	_, err := builder.Build()
+	if err == nil || !strings.Contains(err.Error(), `unknown stop "7"`) {
+		t.Errorf("Build() error = %v, want unknown stop", err)
+	}
+}
+
+func TestWriteZip(t *testing.T) {
+	feed, err := testBuilder().Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := feed.WriteZip(&buf); err != nil {
+		t.Fatalf("WriteZip() error = %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+
+	rows := make(map[string]int)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("Open(%s) error = %v", file.Name, err)
+		}
+		records, err := csv.NewReader(r).ReadAll()
+		r.Close()
+		if err != nil {
+			t.Fatalf("ReadAll(%s) error = %v", file.Name, err)
+		}
+		rows[file.Name] = len(records) - 1
+	}
+
+	want := map[string]int{
+		"agency.txt":         1,
+		"stops.txt":          2,
+		"routes.txt":         1,
+		"trips.txt":          4,
+		"stop_times.txt":     8,
+		"calendar.txt":       2,
+		"calendar_dates.txt": 1,
+		"feed_info.txt":      1,
+	}
+	for name, count := range want {
+		if rows[name] != count {
+			t.Errorf("WriteZip() %s rows = %d, want %d", name, rows[name], count)
+		}
+	}
+}
+
+func TestBuildContingencies(t *testing.T) {
+	adjustment := func(adjType ferries.AdjType, replacedBy *int) []ferries.ContingencyAdjustment {
+		return []ferries.ContingencyAdjustment{
+			{DateFrom: wsdotDate(*local(3, 0, 0)), DateThru: wsdotDate(*local(3, 23, 59)), AdjType: adjType, ReplacedBySchedRouteID: replacedBy},
+		}
+	}
+	contingency := 101
+	missing := 999
+
+	tests := []struct {
+		name        string
+		routes      []ferries.RouteSchedule
+		wantRemoved bool
+	}{
+		{
+			name: "Cancelled",
+			routes: []ferries.RouteSchedule{
+				{SchedRouteID: 100, RouteID: 5, RouteAbbrev: "SEA-BI", ContingencyAdj: adjustment(ferries.AdjTypeCancellation, nil)},
+			},
+			wantRemoved: true,
+		},
+		{
+			name: "Replaced by contingency sched route",
+			routes: []ferries.RouteSchedule{
+				{SchedRouteID: 100, RouteID: 5, RouteAbbrev: "SEA-BI", ContingencyAdj: adjustment(ferries.AdjTypeCancellation, &contingency)},
+				{SchedRouteID: 101, RouteID: 5, RouteAbbrev: "SEA-BI", ContingencyOnly: true},
+			},
+		},
+		{
+			name: "Replacement not published",
+			routes: []ferries.RouteSchedule{
+				{SchedRouteID: 100, RouteID: 5, RouteAbbrev: "SEA-BI", ContingencyAdj: adjustment(ferries.AdjTypeCancellation, &missing)},
+			},
+			wantRemoved: true,
+		},
+		{
+			name: "Contingency sched route added",
+			routes: []ferries.RouteSchedule{
+				{SchedRouteID: 100, RouteID: 5, RouteAbbrev: "SEA-BI", ContingencyAdj: adjustment(ferries.AdjTypeCancellation, nil)},
+				{SchedRouteID: 101, RouteID: 5, RouteAbbrev: "SEA-BI", ContingencyOnly: true, ContingencyAdj: adjustment(ferries.AdjTypeAddition, nil)},
+			},
+		},
+	}
+
+	terminals := []ferries.TerminalLocation{
+		{TerminalID: 7, TerminalName: "Seattle", Latitude: 47.602501, Longitude: -122.340472},
+		{TerminalID: 3, TerminalName: "Bainbridge Island", Latitude: 47.622339, Longitude: -122.509617},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder(terminals, tt.routes)
+			builder.AddSchedule(*local(3, 0, 0), ferries.Schedule{
+				AllRoutes: []int64{5},
+				TerminalCombos: []ferries.TerminalCombo{
+					{DepartingTerminalID: 7, ArrivingTerminalID: 3, Times: []ferries.Time{{DepartingTime: local(3, 5, 30), ArrivingTime: local(3, 6, 5)}}},
+				},
+			})
+
+			feed, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			if removed := len(feed.CalendarDates) == 1; removed != tt.wantRemoved {
+				t.Errorf("Build() calendar dates = %+v, want removed %v", feed.CalendarDates, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestServiceTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		departure *time.Time
+		wantDate  string
+		wantTime  string
+	}{
+		{name: "Morning", departure: local(2, 5, 30), wantDate: "20240602", wantTime: "05:30:00"},
+		{name: "Evening", departure: local(2, 23, 50), wantDate: "20240602", wantTime: "23:50:00"},
+		{name: "After midnight", departure: local(3, 0, 50), wantDate: "20240602", wantTime: "24:50:00"},
+		{name: "At the cutoff", departure: local(3, 3, 0), wantDate: "20240603", wantTime: "03:00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, offset := ServiceTime(*tt.departure)
+			if got := date.Format(dateLayout); got != tt.wantDate {
+				t.Errorf("ServiceTime() date = %v, want %v", got, tt.wantDate)
+			}
+			if got := FormatTime(offset); got != tt.wantTime {
+				t.Errorf("ServiceTime() time = %v, want %v", got, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestWriteZipEmptyRequiredFile(t *testing.T) {
+	feed, err := testBuilder().Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Feed)
+		want   string
+	}{
+		{name: "No trips", modify: func(f *Feed) { f.Trips = nil }, want: "trips.txt"},
+		{name: "No stop times", modify: func(f *Feed) { f.StopTimes = nil }, want: "stop_times.txt"},
+		{name: "No calendars", modify: func(f *Feed) { f.Calendars, f.CalendarDates = nil, nil }, want: "calendar.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modified := *feed
+			tt.modify(&modified)
+
+			var buf bytes.Buffer
+			err := modified.WriteZip(&buf)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("WriteZip() error = %v, want %s error", err, tt.want)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("WriteZip() wrote %d bytes, want none", buf.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package gtfs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Validate checks the feed against the GTFS reference: required fields,
+// unique IDs, references between files and stop time ordering. It returns
+// every problem found, joined.
+func (f *Feed) Validate() error {
+	var problems []error
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if len(f.Agencies) == 0 {
+		problem("agency.txt: no agencies")
+	}
+
+	agencies := make(map[string]struct{})
+	for _, agency := range f.Agencies {
+		if agency.Name == "" || agency.URL == "" || agency.Timezone == "" {
+			problem("agency.txt: agency %q is missing a name, url or timezone", agency.ID)
+		}
+		if _, ok := agencies[agency.ID]; ok {
+			problem("agency.txt: duplicate agency_id %q", agency.ID)
+		}
+		agencies[agency.ID] = struct{}{}
+	}
+
+	stops := make(map[string]struct{})
+	for _, stop := range f.Stops {
+		if stop.ID == "" || stop.Name == "" {
+			problem("stops.txt: stop %q is missing an id or name", stop.ID)
+		}
+		if stop.Latitude < -90 || stop.Latitude > 90 || stop.Longitude < -180 || stop.Longitude > 180 {
+			problem("stops.txt: stop %q has invalid coordinates", stop.ID)
+		}
+		if _, ok := stops[stop.ID]; ok {
+			problem("stops.txt: duplicate stop_id %q", stop.ID)
+		}
+		stops[stop.ID] = struct{}{}
+	}
+
+	routes := make(map[string]struct{})
+	for _, route := range f.Routes {
+		if route.ShortName == "" && route.LongName == "" {
+			problem("routes.txt: route %q has neither a short nor a long name", route.ID)
+		}
+		if _, ok := agencies[route.AgencyID]; !ok && len(f.Agencies) > 1 {
+			problem("routes.txt: route %q references unknown agency %q", route.ID, route.AgencyID)
+		}
+		if _, ok := routes[route.ID]; ok {
+			problem("routes.txt: duplicate route_id %q", route.ID)
+		}
+		routes[route.ID] = struct{}{}
+	}
+
+	services := make(map[string]struct{})
+	for _, calendar := range f.Calendars {
+		if calendar.EndDate.Before(calendar.StartDate) {
+			problem("calendar.txt: service %q ends before it starts", calendar.ServiceID)
+		}
+		if _, ok := services[calendar.ServiceID]; ok {
+			problem("calendar.txt: duplicate service_id %q", calendar.ServiceID)
+		}
+		services[calendar.ServiceID] = struct{}{}
+	}
+	for _, date := range f.CalendarDates {
+		if date.ExceptionType != ExceptionAdded && date.ExceptionType != ExceptionRemoved {
+			problem("calendar_dates.txt: service %q has invalid exception_type %d", date.ServiceID, date.ExceptionType)
+		}
+		services[date.ServiceID] = struct{}{}
+	}
+
+	trips := make(map[string]struct{})
+	for _, trip := range f.Trips {
+		if _, ok := routes[trip.RouteID]; !ok {
+			problem("trips.txt: trip %q references unknown route %q", trip.ID, trip.RouteID)
+		}
+		if _, ok := services[trip.ServiceID]; !ok {
+			problem("trips.txt: trip %q references unknown service %q", trip.ID, trip.ServiceID)
+		}
+		if _, ok := trips[trip.ID]; ok {
+			problem("trips.txt: duplicate trip_id %q", trip.ID)
+		}
+		trips[trip.ID] = struct{}{}
+	}
+
+	type last struct {
+		sequence  int
+		departure time.Duration
+		count     int
+	}
+	seen := make(map[string]*last)
+	for _, stopTime := range f.StopTimes {
+		if _, ok := trips[stopTime.TripID]; !ok {
+			problem("stop_times.txt: unknown trip %q", stopTime.TripID)
+		}
+		if _, ok := stops[stopTime.StopID]; !ok {
+			problem("stop_times.txt: trip %q references unknown stop %q", stopTime.TripID, stopTime.StopID)
+		}
+		if stopTime.DepartureTime < stopTime.ArrivalTime {
+			problem("stop_times.txt: trip %q departs stop %q before arriving", stopTime.TripID, stopTime.StopID)
+		}
+
+		previous, ok := seen[stopTime.TripID]
+		if !ok {
+			seen[stopTime.TripID] = &last{sequence: stopTime.StopSequence, departure: stopTime.DepartureTime, count: 1}
+			continue
+		}
+
+		if stopTime.StopSequence <= previous.sequence {
+			problem("stop_times.txt: trip %q stop_sequence does not increase", stopTime.TripID)
+		}
+		if stopTime.ArrivalTime < previous.departure {
+			problem("stop_times.txt: trip %q travels back in time at stop %q", stopTime.TripID, stopTime.StopID)
+		}
+		previous.sequence, previous.departure = stopTime.StopSequence, stopTime.DepartureTime
+		previous.count++
+	}
+
+	for _, trip := range f.Trips {
+		if l, ok := seen[trip.ID]; !ok || l.count < 2 {
+			problem("trips.txt: trip %q has fewer than two stop times", trip.ID)
+		}
+	}
+
+	return errors.Join(problems...)
+}
@@ -0,0 +1,159 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteZip writes the feed as a GTFS zip archive. It returns an error
+// without writing anything when a required file would have no records.
+// Optional files without records are left out.
+func (f *Feed) WriteZip(w io.Writer) error {
+	calendarDates := f.calendarDateRows()
+
+	files := []struct {
+		name     string
+		rows     [][]string
+		required bool
+	}{
+		{"agency.txt", f.agencyRows(), true},
+		{"stops.txt", f.stopRows(), true},
+		{"routes.txt", f.routeRows(), true},
+		{"trips.txt", f.tripRows(), true},
+		{"stop_times.txt", f.stopTimeRows(), true},
+		// calendar.txt may only be omitted when calendar_dates.txt lists
+		// every service date
+		{"calendar.txt", f.calendarRows(), len(calendarDates) < 2},
+		{"calendar_dates.txt", calendarDates, false},
+		{"feed_info.txt", f.feedInfoRows(), false},
+	}
+
+	for _, file := range files {
+		// the first row is the header
+		if file.required && len(file.rows) < 2 {
+			return fmt.Errorf("error writing %s: no records", file.name)
+		}
+	}
+
+	archive := zip.NewWriter(w)
+
+	for _, file := range files {
+		if len(file.rows) < 2 {
+			continue
+		}
+
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("error creating %s: %v", file.name, err)
+		}
+
+		records := csv.NewWriter(writer)
+		if err := records.WriteAll(file.rows); err != nil {
+			return fmt.Errorf("error writing %s: %v", file.name, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+// FormatTime formats a stop time offset as HH:MM:SS, allowing hours past 24.
+func FormatTime(offset time.Duration) string {
+	seconds := int(offset / time.Second)
+
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+func formatDate(date time.Time) string {
+	return date.Format(dateLayout)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
+
+func formatBool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+func (f *Feed) agencyRows() [][]string {
+	rows := [][]string{{"agency_id", "agency_name", "agency_url", "agency_timezone", "agency_lang", "agency_phone"}}
+	for _, agency := range f.Agencies {
+		rows = append(rows, []string{agency.ID, agency.Name, agency.URL, agency.Timezone, agency.Lang, agency.Phone})
+	}
+	return rows
+}
+
+func (f *Feed) stopRows() [][]string {
+	rows := [][]string{{"stop_id", "stop_code", "stop_name", "stop_lat", "stop_lon"}}
+	for _, stop := range f.Stops {
+		rows = append(rows, []string{stop.ID, stop.Code, stop.Name, formatFloat(stop.Latitude), formatFloat(stop.Longitude)})
+	}
+	return rows
+}
+
+func (f *Feed) routeRows() [][]string {
+	rows := [][]string{{"route_id", "agency_id", "route_short_name", "route_long_name", "route_desc", "route_type"}}
+	for _, route := range f.Routes {
+		rows = append(rows, []string{route.ID, route.AgencyID, route.ShortName, route.LongName, route.Desc, strconv.Itoa(route.Type)})
+	}
+	return rows
+}
+
+func (f *Feed) tripRows() [][]string {
+	rows := [][]string{{"route_id", "service_id", "trip_id", "trip_headsign", "wheelchair_accessible"}}
+	for _, trip := range f.Trips {
+		rows = append(rows, []string{trip.RouteID, trip.ServiceID, trip.ID, trip.Headsign, strconv.Itoa(trip.WheelchairAccessible)})
+	}
+	return rows
+}
+
+func (f *Feed) stopTimeRows() [][]string {
+	rows := [][]string{{"trip_id", "arrival_time", "departure_time", "stop_id", "stop_sequence", "timepoint"}}
+	for _, stopTime := range f.StopTimes {
+		rows = append(rows, []string{
+			stopTime.TripID,
+			FormatTime(stopTime.ArrivalTime),
+			FormatTime(stopTime.DepartureTime),
+			stopTime.StopID,
+			strconv.Itoa(stopTime.StopSequence),
+			formatBool(stopTime.Timepoint),
+		})
+	}
+	return rows
+}
+
+func (f *Feed) calendarRows() [][]string {
+	rows := [][]string{{"service_id", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday", "start_date", "end_date"}}
+	for _, calendar := range f.Calendars {
+		row := []string{calendar.ServiceID}
+		for _, weekday := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+			row = append(row, formatBool(calendar.Days[weekday]))
+		}
+		rows = append(rows, append(row, formatDate(calendar.StartDate), formatDate(calendar.EndDate)))
+	}
+	return rows
+}
+
+func (f *Feed) calendarDateRows() [][]string {
+	rows := [][]string{{"service_id", "date", "exception_type"}}
+	for _, date := range f.CalendarDates {
+		rows = append(rows, []string{date.ServiceID, formatDate(date.Date), strconv.Itoa(date.ExceptionType)})
+	}
+	return rows
+}
+
+func (f *Feed) feedInfoRows() [][]string {
+	rows := [][]string{{"feed_publisher_name", "feed_publisher_url", "feed_lang", "feed_start_date", "feed_end_date"}}
+	if f.FeedInfo != nil {
+		info := f.FeedInfo
+		rows = append(rows, []string{info.PublisherName, info.PublisherURL, info.Lang, formatDate(info.StartDate), formatDate(info.EndDate)})
+	}
+	return rows
+}
@@ -0,0 +1,251 @@
+package gtfsrt
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+	"alpineworks.io/wsdot/ferries/gtfs"
+	"alpineworks.io/wsdot/internal/geo"
+)
+
+var (
+	htmlTagRegexp = regexp.MustCompile(`<[^>]*>`)
+	spaceRegexp   = regexp.MustCompile(`\s+`)
+)
+
+func vehicleDescriptor(location ferries.VesselLocation) *VehicleDescriptor {
+	return &VehicleDescriptor{
+		ID:    strconv.Itoa(location.VesselID),
+		Label: location.VesselName,
+	}
+}
+
+// tripDescriptor identifies the sailing a vessel is on. It returns nil when
+// the vessel is not on a scheduled sailing.
+func tripDescriptor(location ferries.VesselLocation) *TripDescriptor {
+	if !location.InService || location.DepartingTerminalID == 0 || location.ArrivingTerminalID == 0 {
+		return nil
+	}
+
+	scheduled, err := ferries.ParseWSDOTTime(location.ScheduledDeparture)
+	if err != nil {
+		return nil
+	}
+
+	// start date and time are those of the static trip, so sailings after
+	// midnight are on the previous service date
+	serviceDate, startTime := gtfs.ServiceTime(*scheduled)
+
+	return &TripDescriptor{
+		TripID:    gtfs.TripID(int64(location.DepartingTerminalID), int64(location.ArrivingTerminalID), *scheduled),
+		StartDate: serviceDate.Format("20060102"),
+		StartTime: gtfs.FormatTime(startTime),
+	}
+}
+
+func timestamp(location ferries.VesselLocation) time.Time {
+	t, err := ferries.ParseWSDOTTime(location.TimeStamp)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return *t
+}
+
+// VehiclePositionsFeed builds a VehiclePositions feed with one entity per
+// vessel.
+func VehiclePositionsFeed(locations []ferries.VesselLocation, now time.Time) *FeedMessage {
+	feed := &FeedMessage{Header: FeedHeader{Timestamp: now}}
+
+	for _, location := range locations {
+		position := &VehiclePosition{
+			Trip:    tripDescriptor(location),
+			Vehicle: vehicleDescriptor(location),
+			Position: &Position{
+				Latitude:  float32(location.Latitude),
+				Longitude: float32(location.Longitude),
+				Bearing:   float32(location.Heading),
+				Speed:     float32(location.Speed * geo.MetersPerNauticalMile / 3600),
+			},
+			Timestamp: timestamp(location),
+		}
+
+		switch {
+		case location.AtDock && location.DepartingTerminalID != 0:
+			position.CurrentStatus = StoppedAt
+			position.StopID = gtfs.StopID(int64(location.DepartingTerminalID))
+		case location.ArrivingTerminalID != 0:
+			position.CurrentStatus = InTransitTo
+			position.StopID = gtfs.StopID(int64(location.ArrivingTerminalID))
+		}
+
+		feed.Entities = append(feed.Entities, FeedEntity{
+			ID:      fmt.Sprintf("vehicle-%d", location.VesselID),
+			Vehicle: position,
+		})
+	}
+
+	return feed
+}
+
+// TripUpdatesFeed builds a TripUpdates feed for the sailings vessels are
+// currently on. Departure delays come from LeftDock, or from the current time
+// for a vessel still at the dock after its scheduled departure. Arrivals use
+// the estimator when given and the WSDOT ETA otherwise.
+func TripUpdatesFeed(locations []ferries.VesselLocation, estimator *ferries.ETAEstimator, now time.Time) *FeedMessage {
+	feed := &FeedMessage{Header: FeedHeader{Timestamp: now}}
+
+	for _, location := range locations {
+		trip := tripDescriptor(location)
+		if trip == nil {
+			continue
+		}
+
+		scheduled, _ := ferries.ParseWSDOTTime(location.ScheduledDeparture)
+
+		departure := &StopTimeEvent{}
+		switch leftDock, err := ferries.ParseWSDOTTime(location.LeftDock); {
+		case err == nil:
+			departure.Time = *leftDock
+		case location.AtDock && now.After(*scheduled):
+			departure.Time = now
+		default:
+			departure.Time = *scheduled
+		}
+		delay := max(0, departure.Time.Sub(*scheduled)).Truncate(time.Second)
+		departure.Delay = &delay
+
+		update := &TripUpdate{
+			Trip:    *trip,
+			Vehicle: vehicleDescriptor(location),
+			StopTimeUpdates: []StopTimeUpdate{
+				{
+					StopSequence: 1,
+					StopID:       gtfs.StopID(int64(location.DepartingTerminalID)),
+					Departure:    departure,
+				},
+			},
+			Timestamp: timestamp(location),
+			Delay:     &delay,
+		}
+
+		var arrival *time.Time
+		if estimator != nil {
+			if estimate, err := estimator.Estimate(location, now); err == nil {
+				arrival = &estimate.ETA
+			}
+		} else if eta, err := ferries.ParseWSDOTTime(location.Eta); err == nil {
+			arrival = eta
+		}
+
+		if arrival != nil {
+			update.StopTimeUpdates = append(update.StopTimeUpdates, StopTimeUpdate{
+				StopSequence: 2,
+				StopID:       gtfs.StopID(int64(location.ArrivingTerminalID)),
+				Arrival:      &StopTimeEvent{Time: *arrival},
+			})
+		}
+
+		feed.Entities = append(feed.Entities, FeedEntity{
+			ID:         "trip-" + trip.TripID,
+			TripUpdate: update,
+		})
+	}
+
+	return feed
+}
+
+// plainText strips the HTML WSF uses in bulletins.
+func plainText(text string) string {
+	text = htmlTagRegexp.ReplaceAllString(text, " ")
+	text = html.UnescapeString(text)
+
+	return strings.TrimSpace(spaceRegexp.ReplaceAllString(text, " "))
+}
+
+// headline returns the first sentence of the text.
+func headline(text string) string {
+	if i := strings.IndexAny(text, ".!?"); i >= 0 && i < len(text)-1 {
+		return text[:i+1]
+	}
+
+	return text
+}
+
+// AlertsFeed builds a ServiceAlerts feed from the service disruptions of the
+// route schedules and the vessel watch messages of the vessels. A bulletin
+// posted to several routes becomes one alert informing all of them.
+func AlertsFeed(routes []ferries.RouteSchedule, locations []ferries.VesselLocation, now time.Time) *FeedMessage {
+	feed := &FeedMessage{Header: FeedHeader{Timestamp: now}}
+
+	alerts := make(map[int]*Alert)
+	var bulletins []int
+	for _, route := range routes {
+		for _, disruption := range route.ServiceDisruptions {
+			alert, ok := alerts[disruption.BulletinID]
+			if !ok {
+				description := plainText(disruption.DisruptionDescription)
+				alert = &Alert{
+					Cause:           UnknownCause,
+					Effect:          UnknownEffect,
+					HeaderText:      headline(description),
+					DescriptionText: description,
+				}
+				if published, err := ferries.ParseWSDOTTime(disruption.PublishDate); err == nil {
+					alert.ActivePeriods = []TimeRange{{Start: *published}}
+				}
+
+				alerts[disruption.BulletinID] = alert
+				bulletins = append(bulletins, disruption.BulletinID)
+			}
+
+			routeID := gtfs.RouteID(route.RouteID)
+			informed := false
+			for _, entity := range alert.InformedEntities {
+				informed = informed || entity.RouteID == routeID
+			}
+			if !informed {
+				alert.InformedEntities = append(alert.InformedEntities, EntitySelector{RouteID: routeID})
+			}
+		}
+	}
+
+	sort.Ints(bulletins)
+	for _, bulletinID := range bulletins {
+		feed.Entities = append(feed.Entities, FeedEntity{
+			ID:    fmt.Sprintf("bulletin-%d", bulletinID),
+			Alert: alerts[bulletinID],
+		})
+	}
+
+	for _, location := range locations {
+		message := plainText(location.VesselWatchMsg)
+		if message == "" {
+			continue
+		}
+
+		entity := EntitySelector{AgencyID: gtfs.DefaultAgencyID}
+		if trip := tripDescriptor(location); trip != nil {
+			entity = EntitySelector{Trip: trip}
+		}
+
+		feed.Entities = append(feed.Entities, FeedEntity{
+			ID: fmt.Sprintf("vessel-%d", location.VesselID),
+			Alert: &Alert{
+				InformedEntities: []EntitySelector{entity},
+				Cause:            UnknownCause,
+				Effect:           UnknownEffect,
+				HeaderText:       location.VesselName + ": " + headline(message),
+				DescriptionText:  message,
+			},
+		})
+	}
+
+	return feed
+}
@@ -0,0 +1,314 @@
+// Package gtfsrt produces GTFS-Realtime vehicle positions, trip updates and
+// service alerts for WSF. Trips are identified with gtfs.TripID so the feeds
+// line up with a static feed exported by the gtfs package.
+package gtfsrt
+
+import "time"
+
+const (
+	Version     = "2.0"
+	ContentType = "application/x-protobuf"
+)
+
+type Incrementality int
+
+const (
+	FullDataset Incrementality = iota
+	Differential
+)
+
+type VehicleStopStatus int
+
+const (
+	IncomingAt VehicleStopStatus = iota
+	StoppedAt
+	InTransitTo
+)
+
+type TripScheduleRelationship int
+
+const (
+	TripScheduled TripScheduleRelationship = iota
+	TripAdded
+	TripUnscheduled
+	TripCanceled
+)
+
+type Cause int
+
+const (
+	UnknownCause Cause = iota + 1
+	OtherCause
+	TechnicalProblem
+	Strike
+	Demonstration
+	Accident
+	Holiday
+	Weather
+	Maintenance
+	Construction
+	PoliceActivity
+	MedicalEmergency
+)
+
+type Effect int
+
+const (
+	NoService Effect = iota + 1
+	ReducedService
+	SignificantDelays
+	Detour
+	AdditionalService
+	ModifiedService
+	OtherEffect
+	UnknownEffect
+	StopMoved
+)
+
+type FeedMessage struct {
+	Header   FeedHeader
+	Entities []FeedEntity
+}
+
+type FeedHeader struct {
+	Incrementality Incrementality
+	Timestamp      time.Time
+}
+
+// FeedEntity holds exactly one of TripUpdate, Vehicle or Alert.
+type FeedEntity struct {
+	ID         string
+	TripUpdate *TripUpdate
+	Vehicle    *VehiclePosition
+	Alert      *Alert
+}
+
+type TripDescriptor struct {
+	TripID               string
+	RouteID              string
+	StartDate            string
+	StartTime            string
+	ScheduleRelationship TripScheduleRelationship
+}
+
+type VehicleDescriptor struct {
+	ID    string
+	Label string
+}
+
+type Position struct {
+	Latitude  float32
+	Longitude float32
+	Bearing   float32
+	// Speed is in meters per second.
+	Speed float32
+}
+
+type VehiclePosition struct {
+	Trip          *TripDescriptor
+	Vehicle       *VehicleDescriptor
+	Position      *Position
+	CurrentStatus VehicleStopStatus
+	StopID        string
+	Timestamp     time.Time
+}
+
+type StopTimeEvent struct {
+	Delay *time.Duration
+	Time  time.Time
+}
+
+type StopTimeUpdate struct {
+	StopSequence int
+	StopID       string
+	Arrival      *StopTimeEvent
+	Departure    *StopTimeEvent
+}
+
+type TripUpdate struct {
+	Trip            TripDescriptor
+	Vehicle         *VehicleDescriptor
+	StopTimeUpdates []StopTimeUpdate
+	Timestamp       time.Time
+	Delay           *time.Duration
+}
+
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+type EntitySelector struct {
+	AgencyID string
+	RouteID  string
+	StopID   string
+	Trip     *TripDescriptor
+}
+
+type Alert struct {
+	ActivePeriods    []TimeRange
+	InformedEntities []EntitySelector
+	Cause            Cause
+	Effect           Effect
+	URL              string
+	HeaderText       string
+	DescriptionText  string
+}
+
+// Marshal encodes the feed in the protobuf wire format of
+// gtfs-realtime.proto.
+func (m *FeedMessage) Marshal() []byte {
+	var e encoder
+
+	e.message(1, func(e *encoder) {
+		e.string(1, Version)
+		e.uvarint(2, uint64(m.Header.Incrementality))
+		e.uvarint(3, uint64(m.Header.Timestamp.Unix()))
+	})
+
+	for _, entity := range m.Entities {
+		e.message(2, entity.encode)
+	}
+
+	return e.buf
+}
+
+func (f FeedEntity) encode(e *encoder) {
+	e.string(1, f.ID)
+
+	if f.TripUpdate != nil {
+		e.message(3, f.TripUpdate.encode)
+	}
+	if f.Vehicle != nil {
+		e.message(4, f.Vehicle.encode)
+	}
+	if f.Alert != nil {
+		e.message(5, f.Alert.encode)
+	}
+}
+
+func (t TripDescriptor) encode(e *encoder) {
+	e.string(1, t.TripID)
+	e.string(2, t.StartTime)
+	e.string(3, t.StartDate)
+	if t.ScheduleRelationship != TripScheduled {
+		e.uvarint(4, uint64(t.ScheduleRelationship))
+	}
+	e.string(5, t.RouteID)
+}
+
+func (v VehicleDescriptor) encode(e *encoder) {
+	e.string(1, v.ID)
+	e.string(2, v.Label)
+}
+
+func (p Position) encode(e *encoder) {
+	e.float(1, p.Latitude)
+	e.float(2, p.Longitude)
+	e.float(3, p.Bearing)
+	e.float(5, p.Speed)
+}
+
+func (v VehiclePosition) encode(e *encoder) {
+	if v.Trip != nil {
+		e.message(1, v.Trip.encode)
+	}
+	if v.Position != nil {
+		e.message(2, v.Position.encode)
+	}
+	e.uvarint(4, uint64(v.CurrentStatus))
+	if !v.Timestamp.IsZero() {
+		e.uvarint(5, uint64(v.Timestamp.Unix()))
+	}
+	e.string(7, v.StopID)
+	if v.Vehicle != nil {
+		e.message(8, v.Vehicle.encode)
+	}
+}
+
+func (s StopTimeEvent) encode(e *encoder) {
+	if s.Delay != nil {
+		e.int64(1, int64(*s.Delay/time.Second))
+	}
+	if !s.Time.IsZero() {
+		e.int64(2, s.Time.Unix())
+	}
+}
+
+func (s StopTimeUpdate) encode(e *encoder) {
+	e.uvarint(1, uint64(s.StopSequence))
+	if s.Arrival != nil {
+		e.message(2, s.Arrival.encode)
+	}
+	if s.Departure != nil {
+		e.message(3, s.Departure.encode)
+	}
+	e.string(4, s.StopID)
+}
+
+func (t TripUpdate) encode(e *encoder) {
+	e.message(1, t.Trip.encode)
+	for _, update := range t.StopTimeUpdates {
+		e.message(2, update.encode)
+	}
+	if t.Vehicle != nil {
+		e.message(3, t.Vehicle.encode)
+	}
+	if !t.Timestamp.IsZero() {
+		e.uvarint(4, uint64(t.Timestamp.Unix()))
+	}
+	if t.Delay != nil {
+		e.int64(5, int64(*t.Delay/time.Second))
+	}
+}
+
+func (t TimeRange) encode(e *encoder) {
+	if !t.Start.IsZero() {
+		e.uvarint(1, uint64(t.Start.Unix()))
+	}
+	if !t.End.IsZero() {
+		e.uvarint(2, uint64(t.End.Unix()))
+	}
+}
+
+func (s EntitySelector) encode(e *encoder) {
+	e.string(1, s.AgencyID)
+	e.string(2, s.RouteID)
+	if s.Trip != nil {
+		e.message(4, s.Trip.encode)
+	}
+	e.string(5, s.StopID)
+}
+
+func translated(text string) func(*encoder) {
+	return func(e *encoder) {
+		e.message(1, func(e *encoder) {
+			e.string(1, text)
+			e.string(2, "en")
+		})
+	}
+}
+
+func (a Alert) encode(e *encoder) {
+	for _, period := range a.ActivePeriods {
+		e.message(1, period.encode)
+	}
+	for _, entity := range a.InformedEntities {
+		e.message(5, entity.encode)
+	}
+	if a.Cause != 0 {
+		e.uvarint(6, uint64(a.Cause))
+	}
+	if a.Effect != 0 {
+		e.uvarint(7, uint64(a.Effect))
+	}
+	if a.URL != "" {
+		e.message(8, translated(a.URL))
+	}
+	if a.HeaderText != "" {
+		e.message(10, translated(a.HeaderText))
+	}
+	if a.DescriptionText != "" {
+		e.message(11, translated(a.DescriptionText))
+	}
+}
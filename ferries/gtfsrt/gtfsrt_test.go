@@ -0,0 +1,228 @@
+package gtfsrt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+// field is a decoded protobuf field: a varint, a fixed32 or nested bytes.
+type field struct {
+	number int
+	varint uint64
+	bytes  []byte
+}
+
+func decode(t *testing.T, buf []byte) []field {
+	t.Helper()
+
+	var fields []field
+	for len(buf) > 0 {
+		key, n := binary.Uvarint(buf)
+		if n <= 0 {
+			t.Fatalf("invalid key")
+		}
+		buf = buf[n:]
+
+		f := field{number: int(key >> 3)}
+		switch key & 7 {
+		case wireVarint:
+			f.varint, n = binary.Uvarint(buf)
+			buf = buf[n:]
+		case wireFixed32:
+			f.varint = uint64(binary.LittleEndian.Uint32(buf))
+			buf = buf[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(buf)
+			f.bytes = buf[n : n+int(length)]
+			buf = buf[n+int(length):]
+		default:
+			t.Fatalf("unexpected wire type %d", key&7)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+func get(t *testing.T, fields []field, number int) field {
+	t.Helper()
+
+	for _, f := range fields {
+		if f.number == number {
+			return f
+		}
+	}
+
+	t.Fatalf("field %d not found", number)
+	return field{}
+}
+
+func wsdotTime(t time.Time) string {
+	return fmt.Sprintf("/Date(%d-0700)/", t.UnixMilli())
+}
+
+func TestVehiclePositionsFeed(t *testing.T) {
+	now := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+	scheduled := time.Date(2024, 6, 1, 5, 30, 0, 0, ferries.TimeZone())
+
+	feed := VehiclePositionsFeed([]ferries.VesselLocation{
+		{
+			VesselID:            2,
+			VesselName:          "Chimacum",
+			DepartingTerminalID: 7,
+			ArrivingTerminalID:  3,
+			Latitude:            47.6,
+			Longitude:           -122.4,
+			Heading:             270,
+			InService:           true,
+			ScheduledDeparture:  wsdotTime(scheduled),
+			TimeStamp:           wsdotTime(now),
+		},
+	}, now)
+
+	message := decode(t, feed.Marshal())
+
+	header := decode(t, get(t, message, 1).bytes)
+	if got := string(get(t, header, 1).bytes); got != Version {
+		t.Errorf("header version = %v, want %v", got, Version)
+	}
+	if got := int64(get(t, header, 3).varint); got != now.Unix() {
+		t.Errorf("header timestamp = %v, want %v", got, now.Unix())
+	}
+
+	entity := decode(t, get(t, message, 2).bytes)
+	if got := string(get(t, entity, 1).bytes); got != "vehicle-2" {
+		t.Errorf("entity id = %v, want vehicle-2", got)
+	}
+
+	vehicle := decode(t, get(t, entity, 4).bytes)
+	trip := decode(t, get(t, vehicle, 1).bytes)
+	if got := string(get(t, trip, 1).bytes); got != "20240601-7-3-0530" {
+		t.Errorf("trip id = %v, want 20240601-7-3-0530", got)
+	}
+
+	position := decode(t, get(t, vehicle, 2).bytes)
+	if got := math.Float32frombits(uint32(get(t, position, 3).varint)); got != 270 {
+		t.Errorf("bearing = %v, want 270", got)
+	}
+
+	if got := VehicleStopStatus(get(t, vehicle, 4).varint); got != InTransitTo {
+		t.Errorf("current status = %v, want %v", got, InTransitTo)
+	}
+	if got := string(get(t, vehicle, 7).bytes); got != "3" {
+		t.Errorf("stop id = %v, want 3", got)
+	}
+}
+
+func TestTripDescriptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		scheduled     time.Time
+		wantTripID    string
+		wantStartDate string
+		wantStartTime string
+	}{
+		{
+			name:          "Morning sailing",
+			scheduled:     time.Date(2024, 6, 1, 5, 30, 0, 0, ferries.TimeZone()),
+			wantTripID:    "20240601-7-3-0530",
+			wantStartDate: "20240601",
+			wantStartTime: "05:30:00",
+		},
+		{
+			name:          "Sailing after midnight",
+			scheduled:     time.Date(2024, 6, 2, 0, 50, 0, 0, ferries.TimeZone()),
+			wantTripID:    "20240602-7-3-0050",
+			wantStartDate: "20240601",
+			wantStartTime: "24:50:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trip := tripDescriptor(ferries.VesselLocation{
+				DepartingTerminalID: 7,
+				ArrivingTerminalID:  3,
+				InService:           true,
+				ScheduledDeparture:  wsdotTime(tt.scheduled),
+			})
+			if trip == nil {
+				t.Fatalf("tripDescriptor() = nil")
+			}
+
+			if trip.TripID != tt.wantTripID || trip.StartDate != tt.wantStartDate || trip.StartTime != tt.wantStartTime {
+				t.Errorf("tripDescriptor() = %v %v %v, want %v %v %v",
+					trip.TripID, trip.StartDate, trip.StartTime, tt.wantTripID, tt.wantStartDate, tt.wantStartTime)
+			}
+		})
+	}
+}
+
+func TestTripUpdatesFeedDelay(t *testing.T) {
+	scheduled := time.Date(2024, 6, 1, 5, 30, 0, 0, ferries.TimeZone())
+	now := scheduled.Add(20 * time.Minute)
+
+	feed := TripUpdatesFeed([]ferries.VesselLocation{
+		{
+			VesselID:            2,
+			DepartingTerminalID: 7,
+			ArrivingTerminalID:  3,
+			InService:           true,
+			ScheduledDeparture:  wsdotTime(scheduled),
+			LeftDock:            wsdotTime(scheduled.Add(8 * time.Minute)),
+			Eta:                 wsdotTime(scheduled.Add(43 * time.Minute)),
+		},
+	}, nil, now)
+
+	if len(feed.Entities) != 1 {
+		t.Fatalf("TripUpdatesFeed() entities = %d, want 1", len(feed.Entities))
+	}
+
+	update := feed.Entities[0].TripUpdate
+	if got, want := *update.Delay, 8*time.Minute; got != want {
+		t.Errorf("TripUpdatesFeed() delay = %v, want %v", got, want)
+	}
+	if len(update.StopTimeUpdates) != 2 || !update.StopTimeUpdates[1].Arrival.Time.Equal(scheduled.Add(43*time.Minute)) {
+		t.Errorf("TripUpdatesFeed() stop time updates = %+v, want arrival at eta", update.StopTimeUpdates)
+	}
+}
+
+func TestAlertsFeed(t *testing.T) {
+	disruption := ferries.ServiceDisruption{
+		BulletinID:            42,
+		DisruptionDescription: "<p>The 5:30 am sailing is cancelled. Sorry &amp; thanks.</p>",
+	}
+
+	feed := AlertsFeed([]ferries.RouteSchedule{
+		{RouteID: 5, ServiceDisruptions: []ferries.ServiceDisruption{disruption}},
+		{RouteID: 6, ServiceDisruptions: []ferries.ServiceDisruption{disruption}},
+	}, []ferries.VesselLocation{
+		{VesselID: 2, VesselName: "Chimacum", VesselWatchMsg: "Delayed due to tide."},
+		{VesselID: 3},
+	}, time.Now())
+
+	if len(feed.Entities) != 2 {
+		t.Fatalf("AlertsFeed() entities = %d, want 2", len(feed.Entities))
+	}
+
+	bulletin := feed.Entities[0].Alert
+	if got, want := bulletin.HeaderText, "The 5:30 am sailing is cancelled."; got != want {
+		t.Errorf("AlertsFeed() header = %q, want %q", got, want)
+	}
+	if got, want := bulletin.DescriptionText, "The 5:30 am sailing is cancelled. Sorry & thanks."; got != want {
+		t.Errorf("AlertsFeed() description = %q, want %q", got, want)
+	}
+	if len(bulletin.InformedEntities) != 2 {
+		t.Errorf("AlertsFeed() informed entities = %d, want 2", len(bulletin.InformedEntities))
+	}
+
+	if got, want := feed.Entities[1].Alert.HeaderText, "Chimacum: Delayed due to tide."; got != want {
+		t.Errorf("AlertsFeed() vessel header = %q, want %q", got, want)
+	}
+}
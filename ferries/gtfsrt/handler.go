@@ -0,0 +1,117 @@
+package gtfsrt
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/ferries"
+	"alpineworks.io/wsdot/internal/httpcache"
+)
+
+const (
+	DefaultVehiclePositionsCacheDuration = 5 * time.Second
+	DefaultTripUpdatesCacheDuration      = 5 * time.Second
+	DefaultAlertsCacheDuration           = time.Minute
+)
+
+// Handler serves a GTFS-Realtime feed as protobuf. The upstream API is
+// queried at most once per cache duration; concurrent requests share the
+// cached feed.
+type Handler struct {
+	cacheDuration time.Duration
+	estimator     *ferries.ETAEstimator
+	cache         *httpcache.Handler
+}
+
+type HandlerOption func(*Handler)
+
+func WithCacheDuration(cacheDuration time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.cacheDuration = cacheDuration
+	}
+}
+
+// WithETAEstimator predicts arrivals in trip updates with the estimator
+// instead of using the ETA reported by WSDOT.
+func WithETAEstimator(estimator *ferries.ETAEstimator) HandlerOption {
+	return func(h *Handler) {
+		h.estimator = estimator
+	}
+}
+
+func NewVehiclePositionsHandler(ferriesClient *ferries.FerriesClient, options ...HandlerOption) (*Handler, error) {
+	if ferriesClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return newHandler(func(h *Handler) (*FeedMessage, error) {
+		vessels, err := ferriesClient.GetVesselLocations()
+		if err != nil {
+			return nil, err
+		}
+
+		return VehiclePositionsFeed(vessels, time.Now()), nil
+	}, DefaultVehiclePositionsCacheDuration, options...), nil
+}
+
+func NewTripUpdatesHandler(ferriesClient *ferries.FerriesClient, options ...HandlerOption) (*Handler, error) {
+	if ferriesClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return newHandler(func(h *Handler) (*FeedMessage, error) {
+		vessels, err := ferriesClient.GetVesselLocations()
+		if err != nil {
+			return nil, err
+		}
+
+		return TripUpdatesFeed(vessels, h.estimator, time.Now()), nil
+	}, DefaultTripUpdatesCacheDuration, options...), nil
+}
+
+func NewAlertsHandler(ferriesClient *ferries.FerriesClient, options ...HandlerOption) (*Handler, error) {
+	if ferriesClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return newHandler(func(h *Handler) (*FeedMessage, error) {
+		routes, err := ferriesClient.GetRouteSchedules()
+		if err != nil {
+			return nil, err
+		}
+
+		vessels, err := ferriesClient.GetVesselLocations()
+		if err != nil {
+			return nil, err
+		}
+
+		return AlertsFeed(routes, vessels, time.Now()), nil
+	}, DefaultAlertsCacheDuration, options...), nil
+}
+
+func newHandler(fetch func(h *Handler) (*FeedMessage, error), cacheDuration time.Duration, options ...HandlerOption) *Handler {
+	handler := &Handler{
+		cacheDuration: cacheDuration,
+	}
+
+	for _, option := range options {
+		option(handler)
+	}
+
+	handler.cache = httpcache.New("gtfs-realtime feed", ContentType, handler.cacheDuration, func(context.Context) ([]byte, error) {
+		feed, err := fetch(handler)
+		if err != nil {
+			return nil, err
+		}
+
+		return feed.Marshal(), nil
+	})
+
+	return handler
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.cache.ServeHTTP(w, r)
+}
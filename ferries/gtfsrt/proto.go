@@ -0,0 +1,57 @@
+package gtfsrt
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The feed is small and fixed, so it is encoded by hand rather than through
+// generated protobuf code. encoder only implements the wire types the
+// gtfs-realtime.proto messages use.
+type encoder struct {
+	buf []byte
+}
+
+const (
+	wireVarint  = 0
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+func (e *encoder) tag(field, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wireType))
+}
+
+func (e *encoder) uvarint(field int, value uint64) {
+	e.tag(field, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, value)
+}
+
+// int32 and int64 fields are plain varints; negative values take ten bytes.
+func (e *encoder) int64(field int, value int64) {
+	e.uvarint(field, uint64(value))
+}
+
+func (e *encoder) float(field int, value float32) {
+	e.tag(field, wireFixed32)
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, math.Float32bits(value))
+}
+
+func (e *encoder) bytes(field int, value []byte) {
+	e.tag(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(value)))
+	e.buf = append(e.buf, value...)
+}
+
+func (e *encoder) string(field int, value string) {
+	if value == "" {
+		return
+	}
+	e.bytes(field, []byte(value))
+}
+
+func (e *encoder) message(field int, encode func(*encoder)) {
+	var inner encoder
+	encode(&inner)
+	e.bytes(field, inner.buf)
+}
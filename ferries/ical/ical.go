@@ -0,0 +1,236 @@
+// Package ical writes ferry sailings as an RFC 5545 iCalendar so they can be
+// subscribed to or imported into a calendar application.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	productID    = "-//alpineworks.io//wsdot ferries//EN"
+	timeZoneID   = "America/Los_Angeles"
+	dateTimeForm = "20060102T150405"
+	maxLineBytes = 75
+)
+
+// vTimeZone describes America/Los_Angeles with the US daylight saving rules
+// in effect since 2007.
+var vTimeZone = []string{
+	"BEGIN:VTIMEZONE",
+	"TZID:" + timeZoneID,
+	"BEGIN:DAYLIGHT",
+	"TZOFFSETFROM:-0800",
+	"TZOFFSETTO:-0700",
+	"TZNAME:PDT",
+	"DTSTART:19700308T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+	"END:DAYLIGHT",
+	"BEGIN:STANDARD",
+	"TZOFFSETFROM:-0700",
+	"TZOFFSETTO:-0800",
+	"TZNAME:PST",
+	"DTSTART:19701101T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU",
+	"END:STANDARD",
+	"END:VTIMEZONE",
+}
+
+type event struct {
+	combo   *ferries.TerminalCombo
+	sailing ferries.Time
+}
+
+// Calendar collects sailings and writes them as VEVENTs.
+type Calendar struct {
+	name  string
+	alarm time.Duration
+	now   func() time.Time
+
+	events []event
+}
+
+type CalendarOption func(*Calendar)
+
+func NewCalendar(options ...CalendarOption) *Calendar {
+	calendar := &Calendar{
+		now: time.Now,
+	}
+
+	for _, option := range options {
+		option(calendar)
+	}
+
+	return calendar
+}
+
+// WithName sets the calendar name shown by calendar applications.
+func WithName(name string) CalendarOption {
+	return func(c *Calendar) {
+		c.name = name
+	}
+}
+
+// WithAlarm adds a reminder the given time before each departure.
+func WithAlarm(before time.Duration) CalendarOption {
+	return func(c *Calendar) {
+		c.alarm = before
+	}
+}
+
+// AddTerminalCombo adds every sailing of the combo. Call it once per day to
+// build a calendar covering a week.
+func (c *Calendar) AddTerminalCombo(combo ferries.TerminalCombo) {
+	for _, sailing := range combo.Times {
+		if sailing.DepartingTime == nil {
+			continue
+		}
+
+		c.events = append(c.events, event{combo: &combo, sailing: sailing})
+	}
+}
+
+// WriteTo writes the calendar with CRLF line endings and lines folded at 75
+// octets.
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	buf := bufio.NewWriter(counter)
+
+	line := func(content string) {
+		_, _ = buf.WriteString(fold(content))
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:" + productID)
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	if c.name != "" {
+		line("X-WR-CALNAME:" + escape(c.name))
+	}
+	line("X-WR-TIMEZONE:" + timeZoneID)
+
+	for _, content := range vTimeZone {
+		line(content)
+	}
+
+	stamp := c.now().UTC().Format(dateTimeForm) + "Z"
+	for _, e := range c.events {
+		c.writeEvent(line, e, stamp)
+	}
+
+	line("END:VCALENDAR")
+
+	err := buf.Flush()
+
+	return counter.n, err
+}
+
+func (c *Calendar) writeEvent(line func(string), e event, stamp string) {
+	departing := e.sailing.DepartingTime.In(ferries.TimeZone())
+
+	line("BEGIN:VEVENT")
+	line(fmt.Sprintf("UID:%s-%d-%d@wsdot.wa.gov", departing.Format(dateTimeForm), e.combo.DepartingTerminalID, e.combo.ArrivingTerminalID))
+	line("DTSTAMP:" + stamp)
+	line("DTSTART;TZID=" + timeZoneID + ":" + departing.Format(dateTimeForm))
+	if e.sailing.ArrivingTime != nil {
+		line("DTEND;TZID=" + timeZoneID + ":" + e.sailing.ArrivingTime.In(ferries.TimeZone()).Format(dateTimeForm))
+	}
+
+	summary := fmt.Sprintf("%s to %s", e.combo.DepartingTerminalName, e.combo.ArrivingTerminalName)
+	line("SUMMARY:" + escape("Ferry: "+summary))
+	line("LOCATION:" + escape(e.combo.DepartingTerminalName+" ferry terminal"))
+	line("DESCRIPTION:" + escape(description(e)))
+	line("TRANSP:TRANSPARENT")
+
+	if c.alarm > 0 {
+		line("BEGIN:VALARM")
+		line("ACTION:DISPLAY")
+		line(fmt.Sprintf("TRIGGER:-PT%dM", int(c.alarm/time.Minute)))
+		line("DESCRIPTION:" + escape(summary+" departs at "+departing.Format("3:04 PM")))
+		line("END:VALARM")
+	}
+
+	line("END:VEVENT")
+}
+
+func description(e event) string {
+	var lines []string
+
+	if e.sailing.VesselName != "" {
+		vessel := "Vessel: " + e.sailing.VesselName
+		if e.sailing.VesselHandicapAccessible {
+			vessel += " (accessible)"
+		}
+		lines = append(lines, vessel)
+	}
+
+	for _, index := range e.sailing.AnnotationIndexes {
+		if index >= 0 && index < len(e.combo.Annotations) {
+			lines = append(lines, strings.TrimSpace(e.combo.Annotations[index]))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// escape escapes a TEXT value as described in RFC 5545 section 3.3.11.
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// fold splits a content line into lines of at most 75 octets, continuing
+// each with a single space, without splitting UTF-8 sequences.
+func fold(content string) string {
+	var b strings.Builder
+
+	limit := maxLineBytes
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			// no rune starts within the limit, so the content is not valid
+			// UTF-8; cut at the limit rather than not advancing
+			cut = limit
+		}
+
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+
+		// the leading space counts towards the limit
+		limit = maxLineBytes - 1
+	}
+
+	b.WriteString(content)
+	b.WriteString("\r\n")
+
+	return b.String()
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
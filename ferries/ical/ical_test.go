@@ -0,0 +1,106 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "Short line",
+			content: "SUMMARY:Ferry",
+			want:    "SUMMARY:Ferry\r\n",
+		},
+		{
+			name:    "Long line",
+			content: strings.Repeat("a", 80),
+			want:    strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 5) + "\r\n",
+		},
+		{
+			name:    "Multibyte rune at the boundary",
+			content: strings.Repeat("a", 74) + "é" + "b",
+			want:    strings.Repeat("a", 74) + "\r\n é" + "b\r\n",
+		},
+		{
+			name:    "Invalid UTF-8",
+			content: strings.Repeat("\x80", 80),
+			want:    strings.Repeat("\x80", 75) + "\r\n " + strings.Repeat("\x80", 5) + "\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fold(tt.content); got != tt.want {
+				t.Errorf("fold() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	got := escape("Sidney, B.C.; via Friday Harbor\\Anacortes\nno vehicles")
+	want := `Sidney\, B.C.\; via Friday Harbor\\Anacortes\nno vehicles`
+
+	if got != want {
+		t.Errorf("escape() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	departing := time.Date(2024, 7, 4, 5, 30, 0, 0, ferries.TimeZone())
+	arriving := departing.Add(35 * time.Minute)
+
+	calendar := NewCalendar(WithName("Seattle / Bainbridge"), WithAlarm(15*time.Minute))
+	calendar.now = func() time.Time { return time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC) }
+	calendar.AddTerminalCombo(ferries.TerminalCombo{
+		DepartingTerminalID:   7,
+		DepartingTerminalName: "Seattle",
+		ArrivingTerminalID:    3,
+		ArrivingTerminalName:  "Bainbridge Island",
+		Annotations:           []string{"Holiday schedule, no 5:30 am sailing"},
+		Times: []ferries.Time{
+			{DepartingTime: &departing, ArrivingTime: &arriving, VesselName: "Wenatchee", VesselHandicapAccessible: true, AnnotationIndexes: []int{0}},
+		},
+	})
+
+	var buf bytes.Buffer
+	n, err := calendar.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, want %d", n, buf.Len())
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"TZID:America/Los_Angeles\r\n",
+		"DTSTAMP:20240701T000000Z\r\n",
+		"DTSTART;TZID=America/Los_Angeles:20240704T053000\r\n",
+		"DTEND;TZID=America/Los_Angeles:20240704T060500\r\n",
+		"SUMMARY:Ferry: Seattle to Bainbridge Island\r\n",
+		`DESCRIPTION:Vessel: Wenatchee (accessible)\nHoliday schedule\, no 5:30 am s`,
+		"TRIGGER:-PT15M\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("WriteTo() output missing %q", want)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+		if len(line) > maxLineBytes {
+			t.Errorf("WriteTo() line longer than %d octets: %q", maxLineBytes, line)
+		}
+	}
+}
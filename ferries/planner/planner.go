@@ -0,0 +1,457 @@
+// Package planner finds ferry itineraries across terminals, including trips
+// that connect between sailings or routes.
+//
+// The planner works over the sailings of the schedules it is given, so
+// itineraries are limited to the days those schedules cover. Legs carry
+// schedule data; sailing space and fares are added to a found itinerary with
+// AddSailingSpace and AddFares.
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+const (
+	DefaultMinConnectionTime = 15 * time.Minute
+)
+
+var (
+	ErrNoItinerary = errors.New("no itinerary found")
+)
+
+type Leg struct {
+	DepartingTerminalID      int64
+	DepartingTerminalName    string
+	ArrivingTerminalID       int64
+	ArrivingTerminalName     string
+	Departure                time.Time
+	Arrival                  time.Time
+	VesselID                 int64
+	VesselName               string
+	VesselHandicapAccessible bool
+	LoadingRule              ferries.LoadingRule
+	Annotations              []string
+	// Transfer is set for legs made over land between terminals, as
+	// configured with WithTransfer.
+	Transfer bool
+	// ArrivalEstimated is set when the schedule has no arriving time for the
+	// sailing and the arrival was estimated from other sailings.
+	ArrivalEstimated bool
+	// Space is the vehicle space left on the sailing, set by AddSailingSpace.
+	// It is nil when WSF publishes no space for the sailing.
+	Space *Space
+	// Fares are the one-way fares for the sailing, set by AddFares.
+	Fares []ferries.FareLineItem
+}
+
+// Space is the vehicle space left on a sailing. Counts are nil when WSF does
+// not display them for the sailing.
+type Space struct {
+	DriveUp    *int
+	Reservable *int
+	Max        int
+	Cancelled  bool
+}
+
+type Itinerary struct {
+	Legs []Leg
+}
+
+// AddSailingSpace sets Space on the legs found in spaces, typically the
+// GetTerminalSailingSpace result. WSF only publishes space for the next few
+// sailings, so later legs are left unchanged.
+func (i *Itinerary) AddSailingSpace(spaces []ferries.TerminalSailingSpace) {
+	for n := range i.Legs {
+		leg := &i.Legs[n]
+		if leg.Transfer {
+			continue
+		}
+
+		if space, ok := sailingSpace(spaces, *leg); ok {
+			leg.Space = &space
+		}
+	}
+}
+
+func sailingSpace(spaces []ferries.TerminalSailingSpace, leg Leg) (Space, bool) {
+	for _, terminal := range spaces {
+		if int64(terminal.TerminalID) != leg.DepartingTerminalID {
+			continue
+		}
+
+		for _, departing := range terminal.DepartingSpaces {
+			departure, err := ferries.ParseWSDOTTime(departing.Departure)
+			if err != nil || !departure.Equal(leg.Departure) {
+				continue
+			}
+
+			for _, arrival := range departing.SpaceForArrivalTerminals {
+				if int64(arrival.TerminalID) != leg.ArrivingTerminalID && !slices.Contains(arrival.ArrivalTerminalIDs, int(leg.ArrivingTerminalID)) {
+					continue
+				}
+
+				space := Space{
+					Max:       arrival.MaxSpaceCount,
+					Cancelled: departing.IsCancelled,
+				}
+				if arrival.DisplayDriveUpSpace {
+					space.DriveUp = arrival.DriveUpSpaceCount
+				}
+				if arrival.DisplayReservableSpace {
+					space.Reservable = arrival.ReservableSpaceCount
+				}
+
+				return space, true
+			}
+		}
+	}
+
+	return Space{}, false
+}
+
+// FareFetcher returns the fares for a trip between two terminals, typically
+// FerriesClient.GetFareLineItemsBasic.
+type FareFetcher func(tripDate time.Time, departingTerminalID, arrivingTerminalID int, roundTrip bool) ([]ferries.FareLineItem, error)
+
+// AddFares sets the one-way Fares of each sailing in the itinerary.
+func (i *Itinerary) AddFares(fetch FareFetcher) error {
+	for n := range i.Legs {
+		leg := &i.Legs[n]
+		if leg.Transfer {
+			continue
+		}
+
+		fares, err := fetch(leg.Departure, int(leg.DepartingTerminalID), int(leg.ArrivingTerminalID), false)
+		if err != nil {
+			return fmt.Errorf("error fetching fares: %v", err)
+		}
+
+		leg.Fares = fares
+	}
+
+	return nil
+}
+
+func (i Itinerary) Departure() time.Time {
+	return i.Legs[0].Departure
+}
+
+func (i Itinerary) Arrival() time.Time {
+	return i.Legs[len(i.Legs)-1].Arrival
+}
+
+func (i Itinerary) Duration() time.Duration {
+	return i.Arrival().Sub(i.Departure())
+}
+
+type footpath struct {
+	from     int64
+	to       int64
+	duration time.Duration
+}
+
+type Planner struct {
+	connections       []Leg
+	footpathsFrom     map[int64][]footpath
+	footpathsTo       map[int64][]footpath
+	minConnectionTime time.Duration
+	connectionTimes   map[int64]time.Duration
+}
+
+type PlannerOption func(*Planner)
+
+// NewPlanner builds a planner over the sailings in the schedules, typically
+// the GetSchedulesTodayByRouteID result for each route. Sailings that appear
+// in more than one schedule are only counted once.
+func NewPlanner(schedules []ferries.Schedule, options ...PlannerOption) *Planner {
+	planner := &Planner{
+		footpathsFrom:     make(map[int64][]footpath),
+		footpathsTo:       make(map[int64][]footpath),
+		minConnectionTime: DefaultMinConnectionTime,
+		connectionTimes:   make(map[int64]time.Duration),
+	}
+
+	for _, option := range options {
+		option(planner)
+	}
+
+	type sailingKey struct {
+		departing int64
+		arriving  int64
+		departure int64
+		vesselID  int64
+	}
+	seen := make(map[sailingKey]struct{})
+
+	for _, schedule := range schedules {
+		for _, combo := range schedule.TerminalCombos {
+			typical := typicalCrossing(combo)
+
+			for _, sailing := range combo.Times {
+				if sailing.DepartingTime == nil {
+					continue
+				}
+
+				key := sailingKey{combo.DepartingTerminalID, combo.ArrivingTerminalID, sailing.DepartingTime.Unix(), sailing.VesselID}
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+
+				leg := Leg{
+					DepartingTerminalID:      combo.DepartingTerminalID,
+					DepartingTerminalName:    combo.DepartingTerminalName,
+					ArrivingTerminalID:       combo.ArrivingTerminalID,
+					ArrivingTerminalName:     combo.ArrivingTerminalName,
+					Departure:                *sailing.DepartingTime,
+					VesselID:                 sailing.VesselID,
+					VesselName:               sailing.VesselName,
+					VesselHandicapAccessible: sailing.VesselHandicapAccessible,
+					LoadingRule:              sailing.LoadingRule,
+				}
+
+				for _, index := range sailing.AnnotationIndexes {
+					if index >= 0 && index < len(combo.Annotations) {
+						leg.Annotations = append(leg.Annotations, combo.Annotations[index])
+					}
+				}
+
+				switch {
+				case sailing.ArrivingTime != nil:
+					leg.Arrival = *sailing.ArrivingTime
+				case typical > 0:
+					leg.Arrival = sailing.DepartingTime.Add(typical)
+					leg.ArrivalEstimated = true
+				default:
+					continue
+				}
+
+				planner.connections = append(planner.connections, leg)
+			}
+		}
+	}
+
+	sort.SliceStable(planner.connections, func(i, j int) bool {
+		return planner.connections[i].Departure.Before(planner.connections[j].Departure)
+	})
+
+	return planner
+}
+
+// typicalCrossing returns the median crossing time of the combo's sailings
+// that have an arriving time, or zero when none do.
+func typicalCrossing(combo ferries.TerminalCombo) time.Duration {
+	var durations []time.Duration
+	for _, sailing := range combo.Times {
+		if sailing.DepartingTime != nil && sailing.ArrivingTime != nil {
+			durations = append(durations, sailing.ArrivingTime.Sub(*sailing.DepartingTime))
+		}
+	}
+
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	return durations[len(durations)/2]
+}
+
+// WithMinConnectionTime sets how long a rider needs between arriving at a
+// terminal and boarding the next sailing there.
+func WithMinConnectionTime(minConnectionTime time.Duration) PlannerOption {
+	return func(p *Planner) {
+		p.minConnectionTime = minConnectionTime
+	}
+}
+
+// WithTerminalConnectionTime overrides the minimum connection time at one
+// terminal.
+func WithTerminalConnectionTime(terminalID int64, connectionTime time.Duration) PlannerOption {
+	return func(p *Planner) {
+		p.connectionTimes[terminalID] = connectionTime
+	}
+}
+
+// WithTransfer allows travelling over land from one terminal to another in
+// the given time, e.g. driving from Seattle to Anacortes. Transfers are one
+// way; add both directions when needed.
+func WithTransfer(fromTerminalID, toTerminalID int64, duration time.Duration) PlannerOption {
+	return func(p *Planner) {
+		fp := footpath{from: fromTerminalID, to: toTerminalID, duration: duration}
+		p.footpathsFrom[fromTerminalID] = append(p.footpathsFrom[fromTerminalID], fp)
+		p.footpathsTo[toTerminalID] = append(p.footpathsTo[toTerminalID], fp)
+	}
+}
+
+func (p *Planner) connectionTime(terminalID int64) time.Duration {
+	if connectionTime, ok := p.connectionTimes[terminalID]; ok {
+		return connectionTime
+	}
+
+	return p.minConnectionTime
+}
+
+// staysAboard reports whether a rider arriving on leg can stay on the vessel
+// for next, in which case no connection time applies.
+func staysAboard(leg, next Leg) bool {
+	return !leg.Transfer && !next.Transfer && leg.VesselID != 0 && leg.VesselID == next.VesselID && !next.Departure.Before(leg.Arrival)
+}
+
+func transferLeg(fp footpath, departure time.Time) Leg {
+	return Leg{
+		DepartingTerminalID: fp.from,
+		ArrivingTerminalID:  fp.to,
+		Departure:           departure,
+		Arrival:             departure.Add(fp.duration),
+		Transfer:            true,
+	}
+}
+
+// EarliestArrival finds the itinerary from one terminal to another that
+// leaves no earlier than departAfter and arrives soonest.
+func (p *Planner) EarliestArrival(fromTerminalID, toTerminalID int64, departAfter time.Time) (*Itinerary, error) {
+	if fromTerminalID == toTerminalID {
+		return nil, ErrNoItinerary
+	}
+
+	arrival := map[int64]time.Time{fromTerminalID: departAfter}
+	ready := map[int64]time.Time{fromTerminalID: departAfter}
+	parent := make(map[int64]Leg)
+
+	relax := func(terminalID int64) {
+		for _, fp := range p.footpathsFrom[terminalID] {
+			if fp.to == fromTerminalID {
+				continue
+			}
+
+			at := arrival[terminalID].Add(fp.duration)
+			if current, ok := arrival[fp.to]; ok && !at.Before(current) {
+				continue
+			}
+
+			arrival[fp.to] = at
+			ready[fp.to] = at.Add(p.connectionTime(fp.to))
+			parent[fp.to] = transferLeg(fp, arrival[terminalID])
+		}
+	}
+	relax(fromTerminalID)
+
+	for _, c := range p.connections {
+		if best, ok := arrival[toTerminalID]; ok && c.Departure.After(best) {
+			break
+		}
+
+		if r, ok := ready[c.DepartingTerminalID]; !ok || (c.Departure.Before(r) && !staysAboard(parent[c.DepartingTerminalID], c)) {
+			continue
+		}
+
+		if c.ArrivingTerminalID == fromTerminalID {
+			continue
+		}
+
+		if current, ok := arrival[c.ArrivingTerminalID]; ok && !c.Arrival.Before(current) {
+			continue
+		}
+
+		arrival[c.ArrivingTerminalID] = c.Arrival
+		ready[c.ArrivingTerminalID] = c.Arrival.Add(p.connectionTime(c.ArrivingTerminalID))
+		parent[c.ArrivingTerminalID] = c
+		relax(c.ArrivingTerminalID)
+	}
+
+	if _, ok := parent[toTerminalID]; !ok {
+		return nil, ErrNoItinerary
+	}
+
+	var legs []Leg
+	for terminalID := toTerminalID; terminalID != fromTerminalID; {
+		leg, ok := parent[terminalID]
+		if !ok || len(legs) > len(parent) {
+			return nil, ErrNoItinerary
+		}
+
+		legs = append([]Leg{leg}, legs...)
+		terminalID = leg.DepartingTerminalID
+	}
+
+	return &Itinerary{Legs: legs}, nil
+}
+
+type choice struct {
+	leg   Leg
+	leave time.Time
+}
+
+// LatestDeparture finds the itinerary from one terminal to another that
+// arrives no later than arriveBy and leaves as late as possible.
+func (p *Planner) LatestDeparture(fromTerminalID, toTerminalID int64, arriveBy time.Time) (*Itinerary, error) {
+	if fromTerminalID == toTerminalID {
+		return nil, ErrNoItinerary
+	}
+
+	// need is the latest time a rider may arrive at a terminal and still
+	// reach the destination by arriveBy
+	need := map[int64]time.Time{toTerminalID: arriveBy}
+	best := make(map[int64]choice)
+
+	relax := func(terminalID int64) {
+		for _, fp := range p.footpathsTo[terminalID] {
+			if fp.from == toTerminalID {
+				continue
+			}
+
+			leave := need[terminalID].Add(-fp.duration)
+			if current, ok := best[fp.from]; ok && !leave.After(current.leave) {
+				continue
+			}
+
+			best[fp.from] = choice{leg: transferLeg(fp, leave), leave: leave}
+			need[fp.from] = leave
+		}
+	}
+	relax(toTerminalID)
+
+	for i := len(p.connections) - 1; i >= 0; i-- {
+		c := p.connections[i]
+
+		if c.DepartingTerminalID == toTerminalID {
+			continue
+		}
+
+		if n, ok := need[c.ArrivingTerminalID]; !ok || (c.Arrival.After(n) && !staysAboard(c, best[c.ArrivingTerminalID].leg)) {
+			continue
+		}
+
+		if current, ok := best[c.DepartingTerminalID]; ok && !c.Departure.After(current.leave) {
+			continue
+		}
+
+		best[c.DepartingTerminalID] = choice{leg: c, leave: c.Departure}
+		need[c.DepartingTerminalID] = c.Departure.Add(-p.connectionTime(c.DepartingTerminalID))
+		relax(c.DepartingTerminalID)
+	}
+
+	if _, ok := best[fromTerminalID]; !ok {
+		return nil, ErrNoItinerary
+	}
+
+	var legs []Leg
+	for terminalID := fromTerminalID; terminalID != toTerminalID; {
+		next, ok := best[terminalID]
+		if !ok || len(legs) > len(best) {
+			return nil, ErrNoItinerary
+		}
+
+		legs = append(legs, next.leg)
+		terminalID = next.leg.ArrivingTerminalID
+	}
+
+	return &Itinerary{Legs: legs}, nil
+}
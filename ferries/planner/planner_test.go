@@ -0,0 +1,330 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot/ferries"
+)
+
+var base = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func at(hour, minute int) *time.Time {
+	t := base.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	return &t
+}
+
+func sailing(depHour, depMinute, arrHour, arrMinute int) ferries.Time {
+	s := ferries.Time{DepartingTime: at(depHour, depMinute)}
+	if arrHour >= 0 {
+		s.ArrivingTime = at(arrHour, arrMinute)
+	}
+	return s
+}
+
+func on(vesselID int64, times ...ferries.Time) []ferries.Time {
+	for i := range times {
+		times[i].VesselID = vesselID
+	}
+	return times
+}
+
+// terminals: 1 Anacortes, 2 Lopez, 3 Friday Harbor, 4 Orcas, 5 Shaw,
+// 10 Seattle
+func testSchedules() []ferries.Schedule {
+	return []ferries.Schedule{
+		{
+			TerminalCombos: []ferries.TerminalCombo{
+				{
+					DepartingTerminalID: 1, ArrivingTerminalID: 2,
+					Times: on(1, sailing(8, 0, 8, 45), sailing(10, 0, 10, 45)),
+				},
+				{
+					DepartingTerminalID: 2, ArrivingTerminalID: 3,
+					Times: on(2, sailing(8, 55, 9, 40), sailing(9, 10, 9, 55), sailing(11, 0, 11, 45)),
+				},
+				{
+					DepartingTerminalID: 1, ArrivingTerminalID: 3,
+					Times: on(3, sailing(9, 0, 10, 30), sailing(12, 0, -1, 0)),
+				},
+				{
+					DepartingTerminalID: 3, ArrivingTerminalID: 4,
+					Times: on(4, sailing(12, 0, 12, 40)),
+				},
+				{
+					// the 3 to 4 vessel continues to 5
+					DepartingTerminalID: 4, ArrivingTerminalID: 5,
+					Times: on(4, sailing(12, 45, 13, 0)),
+				},
+			},
+		},
+	}
+}
+
+func terminalsOf(itinerary *Itinerary) []int64 {
+	terminals := []int64{itinerary.Legs[0].DepartingTerminalID}
+	for _, leg := range itinerary.Legs {
+		terminals = append(terminals, leg.ArrivingTerminalID)
+	}
+	return terminals
+}
+
+func equal(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEarliestArrival(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []PlannerOption
+		from, to    int64
+		departAfter *time.Time
+		want        []int64
+		wantArrival *time.Time
+		wantErr     error
+	}{
+		{
+			name: "Connection with default connection time",
+			from: 1, to: 3, departAfter: at(7, 0),
+			want: []int64{1, 2, 3}, wantArrival: at(9, 55),
+		},
+		{
+			name:    "Tight connection allowed",
+			options: []PlannerOption{WithTerminalConnectionTime(2, 5*time.Minute)},
+			from:    1, to: 3, departAfter: at(7, 0),
+			want: []int64{1, 2, 3}, wantArrival: at(9, 40),
+		},
+		{
+			name: "Direct sailing",
+			from: 1, to: 3, departAfter: at(8, 30),
+			want: []int64{1, 3}, wantArrival: at(10, 30),
+		},
+		{
+			name: "Estimated arrival is used",
+			from: 1, to: 3, departAfter: at(11, 0),
+			want: []int64{1, 3}, wantArrival: at(13, 30),
+		},
+		{
+			name: "Two connections",
+			from: 1, to: 4, departAfter: at(7, 0),
+			want: []int64{1, 2, 3, 4}, wantArrival: at(12, 40),
+		},
+		{
+			name:    "Land transfer to the first terminal",
+			options: []PlannerOption{WithTransfer(10, 1, 90*time.Minute)},
+			from:    10, to: 2, departAfter: at(6, 0),
+			want: []int64{10, 1, 2}, wantArrival: at(8, 45),
+		},
+		{
+			name: "Through sailing on the same vessel",
+			from: 3, to: 5, departAfter: at(11, 0),
+			want: []int64{3, 4, 5}, wantArrival: at(13, 0),
+		},
+		{
+			name: "No sailings left",
+			from: 3, to: 4, departAfter: at(13, 0),
+			wantErr: ErrNoItinerary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planner := NewPlanner(testSchedules(), tt.options...)
+
+			got, err := planner.EarliestArrival(tt.from, tt.to, *tt.departAfter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EarliestArrival() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if terminals := terminalsOf(got); !equal(terminals, tt.want) {
+				t.Errorf("EarliestArrival() terminals = %v, want %v", terminals, tt.want)
+			}
+			if !got.Arrival().Equal(*tt.wantArrival) {
+				t.Errorf("EarliestArrival() arrival = %v, want %v", got.Arrival(), *tt.wantArrival)
+			}
+		})
+	}
+}
+
+func TestLatestDeparture(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []PlannerOption
+		from, to      int64
+		arriveBy      *time.Time
+		want          []int64
+		wantDeparture *time.Time
+		wantErr       error
+	}{
+		{
+			name: "Direct sailing",
+			from: 1, to: 3, arriveBy: at(10, 30),
+			want: []int64{1, 3}, wantDeparture: at(9, 0),
+		},
+		{
+			name: "Connection is later than direct",
+			from: 1, to: 3, arriveBy: at(12, 0),
+			want: []int64{1, 2, 3}, wantDeparture: at(10, 0),
+		},
+		{
+			name:    "Connection time too short",
+			options: []PlannerOption{WithTerminalConnectionTime(2, 20*time.Minute)},
+			from:    1, to: 3, arriveBy: at(12, 0),
+			want: []int64{1, 3}, wantDeparture: at(9, 0),
+		},
+		{
+			name:    "Land transfer to the first terminal",
+			options: []PlannerOption{WithTransfer(10, 1, 90*time.Minute)},
+			from:    10, to: 2, arriveBy: at(11, 0),
+			want: []int64{10, 1, 2}, wantDeparture: at(8, 15),
+		},
+		{
+			name: "Through sailing on the same vessel",
+			from: 3, to: 5, arriveBy: at(13, 0),
+			want: []int64{3, 4, 5}, wantDeparture: at(12, 0),
+		},
+		{
+			name: "Too early",
+			from: 1, to: 3, arriveBy: at(9, 0),
+			wantErr: ErrNoItinerary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planner := NewPlanner(testSchedules(), tt.options...)
+
+			got, err := planner.LatestDeparture(tt.from, tt.to, *tt.arriveBy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LatestDeparture() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if terminals := terminalsOf(got); !equal(terminals, tt.want) {
+				t.Errorf("LatestDeparture() terminals = %v, want %v", terminals, tt.want)
+			}
+			if !got.Departure().Equal(*tt.wantDeparture) {
+				t.Errorf("LatestDeparture() departure = %v, want %v", got.Departure(), *tt.wantDeparture)
+			}
+		})
+	}
+}
+
+func TestAddSailingSpace(t *testing.T) {
+	count := func(n int) *int { return &n }
+	departure := func(t *time.Time) string {
+		return fmt.Sprintf("/Date(%d-0700)/", t.UnixMilli())
+	}
+
+	spaces := []ferries.TerminalSailingSpace{
+		{
+			TerminalID: 1,
+			DepartingSpaces: []ferries.DepartingSpace{
+				{
+					Departure: departure(at(8, 0)),
+					SpaceForArrivalTerminals: []ferries.ArrivalTerminalSpace{
+						{TerminalID: 2, DisplayDriveUpSpace: true, DriveUpSpaceCount: count(42), ReservableSpaceCount: count(10), MaxSpaceCount: 144},
+					},
+				},
+			},
+		},
+		{
+			TerminalID: 2,
+			DepartingSpaces: []ferries.DepartingSpace{
+				{
+					Departure:   departure(at(9, 10)),
+					IsCancelled: true,
+					SpaceForArrivalTerminals: []ferries.ArrivalTerminalSpace{
+						{TerminalID: 4, ArrivalTerminalIDs: []int{3, 4}, DisplayDriveUpSpace: true, DriveUpSpaceCount: count(0), MaxSpaceCount: 124},
+					},
+				},
+			},
+		},
+	}
+
+	itinerary, err := NewPlanner(testSchedules()).EarliestArrival(1, 4, *at(7, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	itinerary.AddSailingSpace(spaces)
+
+	tests := []struct {
+		name string
+		leg  Leg
+		want *Space
+	}{
+		{name: "Drive-up space only", leg: itinerary.Legs[0], want: &Space{DriveUp: count(42), Max: 144}},
+		{name: "Matched by arrival terminal list", leg: itinerary.Legs[1], want: &Space{DriveUp: count(0), Max: 124, Cancelled: true}},
+		{name: "No space published", leg: itinerary.Legs[2], want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.leg.Space
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("Space = %+v, want %+v", got, tt.want)
+			}
+			if got == nil {
+				return
+			}
+
+			if got.Max != tt.want.Max || got.Cancelled != tt.want.Cancelled || !equalCount(got.DriveUp, tt.want.DriveUp) || !equalCount(got.Reservable, tt.want.Reservable) {
+				t.Errorf("Space = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func equalCount(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestAddFares(t *testing.T) {
+	itinerary, err := NewPlanner(testSchedules(), WithTransfer(10, 1, 90*time.Minute)).EarliestArrival(10, 2, *at(6, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var trips [][2]int
+	err = itinerary.AddFares(func(tripDate time.Time, departingTerminalID, arrivingTerminalID int, roundTrip bool) ([]ferries.FareLineItem, error) {
+		trips = append(trips, [2]int{departingTerminalID, arrivingTerminalID})
+		return []ferries.FareLineItem{{FareLineItem: "Adult", Amount: 16.55}}, nil
+	})
+	if err != nil {
+		t.Fatalf("AddFares() error = %v", err)
+	}
+
+	// the land transfer has no fare
+	if len(trips) != 1 || trips[0] != [2]int{1, 2} {
+		t.Errorf("AddFares() fetched %v, want [[1 2]]", trips)
+	}
+	if itinerary.Legs[0].Fares != nil || len(itinerary.Legs[1].Fares) != 1 {
+		t.Errorf("AddFares() legs = %+v, want fares on the sailing only", itinerary.Legs)
+	}
+
+	wantErr := errors.New("unavailable")
+	err = itinerary.AddFares(func(time.Time, int, int, bool) ([]ferries.FareLineItem, error) {
+		return nil, wantErr
+	})
+	if err == nil {
+		t.Errorf("AddFares() error = nil, want an error")
+	}
+}
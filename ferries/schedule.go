@@ -42,10 +42,18 @@ type ContingencyAdjustment struct {
 	DateThru               string  `json:"DateThru"`
 	EventID                *int    `json:"EventID"`
 	EventDescription       *string `json:"EventDescription"`
-	AdjType                int     `json:"AdjType"`
+	AdjType                AdjType `json:"AdjType"`
 	ReplacedBySchedRouteID *int    `json:"ReplacedBySchedRouteID"`
 }
 
+// AdjType is whether a contingency adjustment adds or cancels a sched route.
+type AdjType int
+
+const (
+	AdjTypeAddition     AdjType = 1
+	AdjTypeCancellation AdjType = 2
+)
+
 func (f *FerriesClient) GetRouteSchedules() ([]RouteSchedule, error) {
 	req, err := http.NewRequest(http.MethodGet, getRouteSchedulesAsJsonURL, nil)
 	if err != nil {
@@ -132,15 +140,15 @@ type TerminalCombo struct {
 }
 
 type Time struct {
-	DepartingTime            *time.Time `json:"DepartingTime"`
-	ArrivingTime             *time.Time `json:"ArrivingTime"`
-	LoadingRule              int        `json:"LoadingRule"`
-	VesselID                 int64      `json:"VesselID"`
-	VesselName               string     `json:"VesselName"`
-	VesselHandicapAccessible bool       `json:"VesselHandicapAccessible"`
-	VesselPositionNum        int        `json:"VesselPositionNum"`
-	Routes                   []int64    `json:"Routes"`
-	AnnotationIndexes        []int      `json:"AnnotationIndexes"`
+	DepartingTime            *time.Time  `json:"DepartingTime"`
+	ArrivingTime             *time.Time  `json:"ArrivingTime"`
+	LoadingRule              LoadingRule `json:"LoadingRule"`
+	VesselID                 int64       `json:"VesselID"`
+	VesselName               string      `json:"VesselName"`
+	VesselHandicapAccessible bool        `json:"VesselHandicapAccessible"`
+	VesselPositionNum        int         `json:"VesselPositionNum"`
+	Routes                   []int64     `json:"Routes"`
+	AnnotationIndexes        []int       `json:"AnnotationIndexes"`
 }
 
 func (f *FerriesClient) GetSchedulesTodayByRouteID(routeID int, onlyRemainingTimes bool) (*Schedule, error) {
@@ -255,7 +263,7 @@ func inTimeToTime(inTime inTime) Time {
 	return Time{
 		DepartingTime:            departingTime,
 		ArrivingTime:             arrivingTime,
-		LoadingRule:              inTime.LoadingRule,
+		LoadingRule:              LoadingRule(inTime.LoadingRule),
 		VesselID:                 inTime.VesselID,
 		VesselName:               inTime.VesselName,
 		VesselHandicapAccessible: inTime.VesselHandicapAccessible,
@@ -265,6 +273,12 @@ func inTimeToTime(inTime inTime) Time {
 	}
 }
 
+// ParseWSDOTTime parses the /Date(milliseconds-offset)/ strings the WSDOT APIs
+// use for timestamps.
+func ParseWSDOTTime(wsdotTime string) (*time.Time, error) {
+	return wsdotTimeStringToTime(wsdotTime)
+}
+
 func wsdotTimeStringToTime(wsdotTime string) (*time.Time, error) {
 	// /Date(1742713200000-0700)/
 	re := regexp.MustCompile(`^/Date\((\d+)([+-]\d{4})\)/$`)
@@ -0,0 +1,195 @@
+package ferries
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"alpineworks.io/wsdot"
+)
+
+const (
+	getTerminalLocationsAsJsonURL = "https://www.wsdot.wa.gov/Ferries/API/Terminals/rest/terminallocations"
+)
+
+type TerminalLocation struct {
+	TerminalID        int     `json:"TerminalID"`
+	TerminalSubjectID int     `json:"TerminalSubjectID"`
+	RegionID          int     `json:"RegionID"`
+	TerminalName      string  `json:"TerminalName"`
+	TerminalAbbrev    string  `json:"TerminalAbbrev"`
+	SortSeq           int     `json:"SortSeq"`
+	Latitude          float64 `json:"Latitude"`
+	Longitude         float64 `json:"Longitude"`
+	AddressLineOne    string  `json:"AddressLineOne"`
+	AddressLineTwo    *string `json:"AddressLineTwo"`
+	City              string  `json:"City"`
+	State             string  `json:"State"`
+	ZipCode           string  `json:"ZipCode"`
+	Country           string  `json:"Country"`
+	MapLink           string  `json:"MapLink"`
+	Directions        string  `json:"Directions"`
+}
+
+func (f *FerriesClient) GetTerminalLocations() ([]TerminalLocation, error) {
+	req, err := http.NewRequest(http.MethodGet, getTerminalLocationsAsJsonURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	q := req.URL.Query()
+	q.Add(wsdot.ParamFerriesAccessCodeKey, f.wsdot.ApiKey)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.wsdot.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var terminals []TerminalLocation
+	if err := json.NewDecoder(resp.Body).Decode(&terminals); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return terminals, nil
+}
+
+const (
+	getTerminalSailingSpaceAsJsonURL = "https://www.wsdot.wa.gov/Ferries/API/Terminals/rest/terminalsailingspace"
+)
+
+type TerminalSailingSpace struct {
+	TerminalID         int              `json:"TerminalID"`
+	TerminalSubjectID  int              `json:"TerminalSubjectID"`
+	RegionID           int              `json:"RegionID"`
+	TerminalName       string           `json:"TerminalName"`
+	TerminalAbbrev     string           `json:"TerminalAbbrev"`
+	SortSeq            int              `json:"SortSeq"`
+	DepartingSpaces    []DepartingSpace `json:"DepartingSpaces"`
+	IsNoFareCollected  *bool            `json:"IsNoFareCollected"`
+	NoFareCollectedMsg *string          `json:"NoFareCollectedMsg"`
+}
+
+// DepartingSpace is the vehicle space left on an upcoming sailing from a
+// terminal, broken down by the terminals it arrives at.
+type DepartingSpace struct {
+	Departure                string                 `json:"Departure"`
+	IsCancelled              bool                   `json:"IsCancelled"`
+	VesselID                 int                    `json:"VesselID"`
+	VesselName               string                 `json:"VesselName"`
+	MaxSpaceCount            int                    `json:"MaxSpaceCount"`
+	SpaceForArrivalTerminals []ArrivalTerminalSpace `json:"SpaceForArrivalTerminals"`
+}
+
+type ArrivalTerminalSpace struct {
+	TerminalID              int     `json:"TerminalID"`
+	TerminalName            string  `json:"TerminalName"`
+	VesselID                int     `json:"VesselID"`
+	VesselName              string  `json:"VesselName"`
+	DisplayReservableSpace  bool    `json:"DisplayReservableSpace"`
+	ReservableSpaceCount    *int    `json:"ReservableSpaceCount"`
+	ReservableSpaceHexColor *string `json:"ReservableSpaceHexColor"`
+	DisplayDriveUpSpace     bool    `json:"DisplayDriveUpSpace"`
+	DriveUpSpaceCount       *int    `json:"DriveUpSpaceCount"`
+	DriveUpSpaceHexColor    *string `json:"DriveUpSpaceHexColor"`
+	MaxSpaceCount           int     `json:"MaxSpaceCount"`
+	ArrivalTerminalIDs      []int   `json:"ArrivalTerminalIDs"`
+}
+
+// GetTerminalSailingSpace returns the vehicle space left on the next
+// sailings from each terminal. WSF only publishes space for routes that
+// carry vehicles, and only for sailings in the next few hours.
+func (f *FerriesClient) GetTerminalSailingSpace() ([]TerminalSailingSpace, error) {
+	req, err := http.NewRequest(http.MethodGet, getTerminalSailingSpaceAsJsonURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	q := req.URL.Query()
+	q.Add(wsdot.ParamFerriesAccessCodeKey, f.wsdot.ApiKey)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.wsdot.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var spaces []TerminalSailingSpace
+	if err := json.NewDecoder(resp.Body).Decode(&spaces); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return spaces, nil
+}
+
+const (
+	getTerminalBulletinsAsJsonURL = "https://www.wsdot.wa.gov/Ferries/API/Terminals/rest/terminalbulletins"
+)
+
+type TerminalBulletin struct {
+	TerminalID        int        `json:"TerminalID"`
+	TerminalSubjectID int        `json:"TerminalSubjectID"`
+	RegionID          int        `json:"RegionID"`
+	TerminalName      string     `json:"TerminalName"`
+	TerminalAbbrev    string     `json:"TerminalAbbrev"`
+	SortSeq           int        `json:"SortSeq"`
+	Bulletins         []Bulletin `json:"Bulletins"`
+}
+
+// Bulletin is an alert posted to a terminal. Terminal bulletins have no id,
+// so they are identified by terminal and title.
+type Bulletin struct {
+	BulletinTitle               string `json:"BulletinTitle"`
+	BulletinText                string `json:"BulletinText"`
+	BulletinSortSeq             int    `json:"BulletinSortSeq"`
+	BulletinLastUpdated         string `json:"BulletinLastUpdated"`
+	BulletinLastUpdatedSortable string `json:"BulletinLastUpdatedSortable"`
+}
+
+// LastUpdated parses BulletinLastUpdated.
+func (b Bulletin) LastUpdated() (*time.Time, error) {
+	return wsdotTimeStringToTime(b.BulletinLastUpdated)
+}
+
+// GetTerminalBulletins returns the alerts currently posted to each terminal.
+func (f *FerriesClient) GetTerminalBulletins() ([]TerminalBulletin, error) {
+	req, err := http.NewRequest(http.MethodGet, getTerminalBulletinsAsJsonURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	q := req.URL.Query()
+	q.Add(wsdot.ParamFerriesAccessCodeKey, f.wsdot.ApiKey)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.wsdot.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var bulletins []TerminalBulletin
+	if err := json.NewDecoder(resp.Body).Decode(&bulletins); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return bulletins, nil
+}
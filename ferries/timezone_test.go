@@ -0,0 +1,38 @@
+package ferries
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeZone(t *testing.T) {
+	tests := []struct {
+		name       string
+		time       time.Time
+		wantOffset int
+	}{
+		{name: "Standard time", time: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), wantOffset: -8 * 60 * 60},
+		{name: "Daylight saving time", time: time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC), wantOffset: -7 * 60 * 60},
+		{name: "Before spring forward", time: time.Date(2024, 3, 10, 9, 59, 0, 0, time.UTC), wantOffset: -8 * 60 * 60},
+		{name: "After spring forward", time: time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC), wantOffset: -7 * 60 * 60},
+		{name: "After fall back", time: time.Date(2024, 11, 3, 9, 0, 0, 0, time.UTC), wantOffset: -8 * 60 * 60},
+	}
+
+	locations := []struct {
+		name     string
+		location *time.Location
+	}{
+		{name: "zoneinfo", location: TimeZone()},
+		{name: "fallback", location: pacificFallback()},
+	}
+
+	for _, l := range locations {
+		for _, tt := range tests {
+			t.Run(l.name+"/"+tt.name, func(t *testing.T) {
+				if _, offset := tt.time.In(l.location).Zone(); offset != tt.wantOffset {
+					t.Errorf("offset = %v, want %v", offset, tt.wantOffset)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package ferries
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"alpineworks.io/wsdot"
+)
+
+const (
+	DefaultVesselWatcherInterval   = 5 * time.Second
+	DefaultVesselWatcherMaxBackoff = 2 * time.Minute
+)
+
+// VesselWatcher polls GetVesselLocations and delivers a VesselLocation each
+// time a vessel reports a new TimeStamp. Updates go to the callback when one
+// is configured, otherwise to the Updates channel. Vessels missing from the
+// latest poll, such as those taken out of service, are dropped from the
+// snapshot.
+type VesselWatcher struct {
+	interval     time.Duration
+	maxBackoff   time.Duration
+	callback     func(VesselLocation)
+	errorHandler func(error)
+
+	getVesselLocations func() ([]VesselLocation, error)
+
+	updates chan VesselLocation
+
+	mu       sync.RWMutex
+	vessels  map[int]VesselLocation
+	lastPoll time.Time
+}
+
+type VesselWatcherOption func(*VesselWatcher)
+
+func NewVesselWatcher(ferriesClient *FerriesClient, options ...VesselWatcherOption) (*VesselWatcher, error) {
+	if ferriesClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	watcher := &VesselWatcher{
+		interval:           DefaultVesselWatcherInterval,
+		maxBackoff:         DefaultVesselWatcherMaxBackoff,
+		getVesselLocations: ferriesClient.GetVesselLocations,
+		updates:            make(chan VesselLocation, 32),
+		vessels:            make(map[int]VesselLocation),
+	}
+
+	for _, option := range options {
+		option(watcher)
+	}
+
+	return watcher, nil
+}
+
+func WithVesselWatcherInterval(interval time.Duration) VesselWatcherOption {
+	return func(w *VesselWatcher) {
+		w.interval = interval
+	}
+}
+
+// WithVesselWatcherMaxBackoff caps the delay between polls after consecutive
+// errors. The delay doubles from the poll interval on every failed poll.
+func WithVesselWatcherMaxBackoff(maxBackoff time.Duration) VesselWatcherOption {
+	return func(w *VesselWatcher) {
+		w.maxBackoff = maxBackoff
+	}
+}
+
+// WithVesselWatcherCallback delivers updates by calling callback from the
+// polling goroutine instead of sending them on the Updates channel.
+func WithVesselWatcherCallback(callback func(VesselLocation)) VesselWatcherOption {
+	return func(w *VesselWatcher) {
+		w.callback = callback
+	}
+}
+
+func WithVesselWatcherErrorHandler(handler func(error)) VesselWatcherOption {
+	return func(w *VesselWatcher) {
+		w.errorHandler = handler
+	}
+}
+
+// Updates returns the channel updates are delivered on. It is closed when Run
+// returns.
+func (w *VesselWatcher) Updates() <-chan VesselLocation {
+	return w.updates
+}
+
+// Snapshot returns the latest known location of every vessel, ordered by
+// VesselID.
+func (w *VesselWatcher) Snapshot() []VesselLocation {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	vessels := make([]VesselLocation, 0, len(w.vessels))
+	for _, vessel := range w.vessels {
+		vessels = append(vessels, vessel)
+	}
+
+	sort.Slice(vessels, func(i, j int) bool {
+		return vessels[i].VesselID < vessels[j].VesselID
+	})
+
+	return vessels
+}
+
+func (w *VesselWatcher) Vessel(vesselID int) (VesselLocation, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	vessel, ok := w.vessels[vesselID]
+
+	return vessel, ok
+}
+
+// LastPoll returns when the watcher last received vessel locations.
+func (w *VesselWatcher) LastPoll() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.lastPoll
+}
+
+// Run polls until ctx is cancelled. GetVesselLocations takes no context, so
+// ctx is only checked between polls and while delivering updates; a request
+// in flight is not interrupted. Give the client passed to wsdot.WithHTTPClient
+// a timeout to bound how long Run takes to return.
+func (w *VesselWatcher) Run(ctx context.Context) error {
+	defer close(w.updates)
+
+	failures := 0
+	for {
+		delay := w.interval
+		if err := w.poll(ctx); err != nil {
+			if w.errorHandler != nil {
+				w.errorHandler(err)
+			}
+
+			failures++
+			delay = backoff(w.interval, w.maxBackoff, failures)
+		} else {
+			failures = 0
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func backoff(interval, maxBackoff time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 0; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return max(interval, min(delay, maxBackoff))
+}
+
+func (w *VesselWatcher) poll(ctx context.Context) error {
+	vessels, err := w.getVesselLocations()
+	if err != nil {
+		return err
+	}
+
+	var changed []VesselLocation
+	current := make(map[int]VesselLocation, len(vessels))
+
+	w.mu.Lock()
+	w.lastPoll = time.Now()
+	for _, vessel := range vessels {
+		current[vessel.VesselID] = vessel
+
+		previous, ok := w.vessels[vessel.VesselID]
+		if ok && previous.TimeStamp == vessel.TimeStamp {
+			continue
+		}
+
+		changed = append(changed, vessel)
+	}
+	w.vessels = current
+	w.mu.Unlock()
+
+	for _, vessel := range changed {
+		if w.callback != nil {
+			w.callback(vessel)
+			continue
+		}
+
+		select {
+		case w.updates <- vessel:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package ferries
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		interval   time.Duration
+		maxBackoff time.Duration
+		failures   int
+		want       time.Duration
+	}{
+		{name: "No failures", interval: 5 * time.Second, maxBackoff: time.Minute, failures: 0, want: 5 * time.Second},
+		{name: "One failure", interval: 5 * time.Second, maxBackoff: time.Minute, failures: 1, want: 10 * time.Second},
+		{name: "Three failures", interval: 5 * time.Second, maxBackoff: time.Minute, failures: 3, want: 40 * time.Second},
+		{name: "Capped", interval: 5 * time.Second, maxBackoff: time.Minute, failures: 10, want: time.Minute},
+		{name: "Max below interval", interval: 5 * time.Second, maxBackoff: time.Second, failures: 2, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backoff(tt.interval, tt.maxBackoff, tt.failures); got != tt.want {
+				t.Errorf("backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVesselWatcherPoll(t *testing.T) {
+	first := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(5 * time.Second)
+
+	location := func(vesselID int, timestamp time.Time) VesselLocation {
+		return VesselLocation{VesselID: vesselID, TimeStamp: wsdotTime(timestamp)}
+	}
+
+	polls := [][]VesselLocation{
+		{location(2, first), location(1, first)},
+		{location(2, first), location(1, second)},
+		{location(2, first), location(1, second)},
+	}
+
+	tests := []struct {
+		name string
+		want []int
+	}{
+		{name: "All vessels are new", want: []int{2, 1}},
+		{name: "Only the vessel with a new timestamp", want: []int{1}},
+		{name: "No new timestamps", want: nil},
+	}
+
+	poll := 0
+	watcher := &VesselWatcher{
+		getVesselLocations: func() ([]VesselLocation, error) { return polls[poll], nil },
+		updates:            make(chan VesselLocation, 32),
+		vessels:            make(map[int]VesselLocation),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := watcher.poll(context.Background()); err != nil {
+				t.Fatalf("poll() error = %v", err)
+			}
+			poll++
+
+			var got []int
+			for len(watcher.updates) > 0 {
+				got = append(got, (<-watcher.updates).VesselID)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("poll() updates = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("poll() updates = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	snapshot := watcher.Snapshot()
+	if len(snapshot) != 2 || snapshot[0].VesselID != 1 || snapshot[1].VesselID != 2 {
+		t.Fatalf("Snapshot() = %+v, want vessels 1 and 2", snapshot)
+	}
+	if snapshot[0].TimeStamp != wsdotTime(second) {
+		t.Errorf("Snapshot() vessel 1 timestamp = %v, want %v", snapshot[0].TimeStamp, wsdotTime(second))
+	}
+
+	if _, ok := watcher.Vessel(3); ok {
+		t.Errorf("Vessel(3) ok = true, want false")
+	}
+	if watcher.LastPoll().IsZero() {
+		t.Errorf("LastPoll() is zero after polling")
+	}
+}
+
+func TestVesselWatcherPollPrunes(t *testing.T) {
+	polls := [][]VesselLocation{
+		{{VesselID: 1, TimeStamp: "a"}, {VesselID: 2, TimeStamp: "a"}},
+		{{VesselID: 1, TimeStamp: "a"}},
+	}
+
+	poll := 0
+	watcher := &VesselWatcher{
+		getVesselLocations: func() ([]VesselLocation, error) { return polls[poll], nil },
+		updates:            make(chan VesselLocation, 32),
+		vessels:            make(map[int]VesselLocation),
+	}
+
+	for poll = range polls {
+		if err := watcher.poll(context.Background()); err != nil {
+			t.Fatalf("poll() error = %v", err)
+		}
+	}
+
+	if snapshot := watcher.Snapshot(); len(snapshot) != 1 || snapshot[0].VesselID != 1 {
+		t.Errorf("Snapshot() = %+v, want vessel 1 only", snapshot)
+	}
+	if _, ok := watcher.Vessel(2); ok {
+		t.Errorf("Vessel(2) ok = true after it left the feed, want false")
+	}
+}
+
+func TestVesselWatcherCallback(t *testing.T) {
+	var got []int
+	watcher := &VesselWatcher{
+		callback: func(location VesselLocation) { got = append(got, location.VesselID) },
+		getVesselLocations: func() ([]VesselLocation, error) {
+			return []VesselLocation{{VesselID: 1, TimeStamp: "a"}, {VesselID: 2, TimeStamp: "a"}}, nil
+		},
+		updates: make(chan VesselLocation),
+		vessels: make(map[int]VesselLocation),
+	}
+
+	if err := watcher.poll(context.Background()); err != nil {
+		t.Fatalf("poll() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Errorf("callback got %v, want vessels 1 and 2", got)
+	}
+}
@@ -0,0 +1,144 @@
+// Package geojson encodes cameras and vessel locations as GeoJSON
+// FeatureCollections for web map layers.
+package geojson
+
+import (
+	"encoding/json"
+
+	"alpineworks.io/wsdot/cameras"
+	"alpineworks.io/wsdot/ferries"
+)
+
+const (
+	ContentType = "application/geo+json"
+
+	typeFeatureCollection = "FeatureCollection"
+	typeFeature           = "Feature"
+	typePoint             = "Point"
+)
+
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+type Feature struct {
+	Type       string         `json:"type"`
+	ID         any            `json:"id,omitempty"`
+	Geometry   *Geometry      `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// CoordinateSource selects which camera coordinates are used for the feature
+// geometry. WSDOT offsets the display coordinates of cameras that share a
+// location so their markers do not overlap.
+type CoordinateSource int
+
+const (
+	DisplayCoordinates CoordinateSource = iota
+	ActualCoordinates
+)
+
+func point(latitude, longitude float64) *Geometry {
+	// GeoJSON positions are longitude first
+	return &Geometry{
+		Type:        typePoint,
+		Coordinates: []float64{longitude, latitude},
+	}
+}
+
+func CamerasFeatureCollection(cams []cameras.Camera, source CoordinateSource) FeatureCollection {
+	collection := FeatureCollection{
+		Type:     typeFeatureCollection,
+		Features: make([]Feature, 0, len(cams)),
+	}
+
+	for _, camera := range cams {
+		latitude, longitude := camera.CameraLocation.Latitude, camera.CameraLocation.Longitude
+		if source == DisplayCoordinates && (camera.DisplayLatitude != 0 || camera.DisplayLongitude != 0) {
+			latitude, longitude = camera.DisplayLatitude, camera.DisplayLongitude
+		}
+
+		collection.Features = append(collection.Features, Feature{
+			Type:     typeFeature,
+			ID:       camera.CameraID,
+			Geometry: point(latitude, longitude),
+			Properties: map[string]any{
+				"cameraID":    camera.CameraID,
+				"title":       camera.Title,
+				"description": camera.Description,
+				"roadName":    camera.CameraLocation.RoadName,
+				"milePost":    camera.CameraLocation.MilePost,
+				"direction":   camera.CameraLocation.Direction,
+				"region":      camera.Region,
+				"owner":       camera.CameraOwner,
+				"ownerURL":    camera.OwnerURL,
+				"imageURL":    camera.ImageURL,
+				"imageWidth":  camera.ImageWidth,
+				"imageHeight": camera.ImageHeight,
+				"isActive":    camera.IsActive,
+			},
+		})
+	}
+
+	return collection
+}
+
+func VesselsFeatureCollection(vessels []ferries.VesselLocation) FeatureCollection {
+	collection := FeatureCollection{
+		Type:     typeFeatureCollection,
+		Features: make([]Feature, 0, len(vessels)),
+	}
+
+	for _, vessel := range vessels {
+		route := ""
+		if len(vessel.OpRouteAbbrev) > 0 {
+			route = vessel.OpRouteAbbrev[0]
+		}
+
+		collection.Features = append(collection.Features, Feature{
+			Type:     typeFeature,
+			ID:       vessel.VesselID,
+			Geometry: point(vessel.Latitude, vessel.Longitude),
+			Properties: map[string]any{
+				"vesselID":                vessel.VesselID,
+				"vesselName":              vessel.VesselName,
+				"mmsi":                    vessel.Mmsi,
+				"heading":                 vessel.Heading,
+				"speed":                   vessel.Speed,
+				"route":                   route,
+				"routes":                  vessel.OpRouteAbbrev,
+				"departingTerminalName":   vessel.DepartingTerminalName,
+				"departingTerminalAbbrev": vessel.DepartingTerminalAbbrev,
+				"arrivingTerminalName":    vessel.ArrivingTerminalName,
+				"arrivingTerminalAbbrev":  vessel.ArrivingTerminalAbbrev,
+				"inService":               vessel.InService,
+				"atDock":                  vessel.AtDock,
+			},
+		})
+	}
+
+	return collection
+}
+
+// Cameras marshals a camera list as a GeoJSON FeatureCollection.
+type Cameras struct {
+	Cameras     []cameras.Camera
+	Coordinates CoordinateSource
+}
+
+func (c Cameras) MarshalJSON() ([]byte, error) {
+	return json.Marshal(CamerasFeatureCollection(c.Cameras, c.Coordinates))
+}
+
+// Vessels marshals vessel locations as a GeoJSON FeatureCollection.
+type Vessels []ferries.VesselLocation
+
+func (v Vessels) MarshalJSON() ([]byte, error) {
+	return json.Marshal(VesselsFeatureCollection(v))
+}
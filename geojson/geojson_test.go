@@ -0,0 +1,114 @@
+package geojson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"alpineworks.io/wsdot/cameras"
+)
+
+func TestCamerasFeatureCollection(t *testing.T) {
+	cams := []cameras.Camera{
+		{
+			CameraID:         9818,
+			Title:            "I-5 at NE 45th St",
+			Description:      cameras.NewNullableString("Looking north"),
+			CameraLocation:   cameras.CameraLocation{Latitude: 47.6612, Longitude: -122.3230, RoadName: "I-5"},
+			DisplayLatitude:  47.6615,
+			DisplayLongitude: -122.3233,
+			IsActive:         true,
+		},
+		{
+			CameraID:       9819,
+			Title:          "SR 520 at Montlake",
+			CameraLocation: cameras.CameraLocation{Latitude: 47.6440, Longitude: -122.3040},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		source CoordinateSource
+		want   [][]float64
+	}{
+		{
+			name:   "display coordinates",
+			source: DisplayCoordinates,
+			want:   [][]float64{{-122.3233, 47.6615}, {-122.3040, 47.6440}},
+		},
+		{
+			name:   "actual coordinates",
+			source: ActualCoordinates,
+			want:   [][]float64{{-122.3230, 47.6612}, {-122.3040, 47.6440}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection := CamerasFeatureCollection(cams, tt.source)
+
+			var got [][]float64
+			for _, feature := range collection.Features {
+				got = append(got, feature.Geometry.Coordinates)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CamerasFeatureCollection() coordinates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVesselsJSON(t *testing.T) {
+	vessels := Vessels{
+		{VesselID: 2, VesselName: "Chimacum", Latitude: 47.6025, Longitude: -122.3388, OpRouteAbbrev: []string{"sea-br"}, InService: true},
+	}
+
+	data, err := json.Marshal(vessels)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type     string `json:"type"`
+			ID       int    `json:"id"`
+			Geometry struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]any `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if got.Type != "FeatureCollection" || len(got.Features) != 1 {
+		t.Fatalf("Vessels = %s, want a FeatureCollection with one feature", data)
+	}
+
+	feature := got.Features[0]
+	if feature.Type != "Feature" || feature.ID != 2 || feature.Geometry.Type != "Point" {
+		t.Errorf("feature = %+v, want a Point feature with id 2", feature)
+	}
+	if want := []float64{-122.3388, 47.6025}; !reflect.DeepEqual(feature.Geometry.Coordinates, want) {
+		t.Errorf("coordinates = %v, want %v", feature.Geometry.Coordinates, want)
+	}
+	if feature.Properties["vesselName"] != "Chimacum" || feature.Properties["route"] != "sea-br" {
+		t.Errorf("properties = %v, want vesselName Chimacum on route sea-br", feature.Properties)
+	}
+}
+
+func TestVesselsFeatureCollectionEmpty(t *testing.T) {
+	data, err := json.Marshal(Vessels(nil))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	// an empty layer must still be a valid FeatureCollection
+	if want := `{"type":"FeatureCollection","features":[]}`; string(data) != want {
+		t.Errorf("Vessels(nil) = %s, want %s", data, want)
+	}
+
+}
@@ -0,0 +1,88 @@
+package geojson
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/cameras"
+	"alpineworks.io/wsdot/ferries"
+	"alpineworks.io/wsdot/internal/httpcache"
+)
+
+const (
+	DefaultCamerasCacheDuration = 5 * time.Minute
+	DefaultVesselsCacheDuration = 5 * time.Second
+)
+
+// Handler serves a live GeoJSON layer. The upstream API is queried at most
+// once per cache duration; concurrent requests share the cached layer.
+type Handler struct {
+	cacheDuration time.Duration
+	cache         *httpcache.Handler
+}
+
+type HandlerOption func(*Handler)
+
+func WithCacheDuration(cacheDuration time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.cacheDuration = cacheDuration
+	}
+}
+
+func NewCamerasHandler(camerasClient *cameras.CamerasClient, source CoordinateSource, options ...HandlerOption) (*Handler, error) {
+	if camerasClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return newHandler(func() (FeatureCollection, error) {
+		cams, err := camerasClient.GetCameras()
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		return CamerasFeatureCollection(cams, source), nil
+	}, DefaultCamerasCacheDuration, options...), nil
+}
+
+func NewVesselsHandler(ferriesClient *ferries.FerriesClient, options ...HandlerOption) (*Handler, error) {
+	if ferriesClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return newHandler(func() (FeatureCollection, error) {
+		vessels, err := ferriesClient.GetVesselLocations()
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		return VesselsFeatureCollection(vessels), nil
+	}, DefaultVesselsCacheDuration, options...), nil
+}
+
+func newHandler(fetch func() (FeatureCollection, error), cacheDuration time.Duration, options ...HandlerOption) *Handler {
+	handler := &Handler{
+		cacheDuration: cacheDuration,
+	}
+
+	for _, option := range options {
+		option(handler)
+	}
+
+	handler.cache = httpcache.New("geojson layer", ContentType, handler.cacheDuration, func(context.Context) ([]byte, error) {
+		collection, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(collection)
+	})
+
+	return handler
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.cache.ServeHTTP(w, r)
+}
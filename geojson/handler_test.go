@@ -0,0 +1,84 @@
+package geojson
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	fetches := 0
+	handler := newHandler(func() (FeatureCollection, error) {
+		fetches++
+		return VesselsFeatureCollection(nil), nil
+	}, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/vessels", nil))
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("ServeHTTP() status = %d, want %d", recorder.Code, http.StatusOK)
+		}
+		if got := recorder.Header().Get("Content-Type"); got != ContentType {
+			t.Errorf("Content-Type = %q, want %q", got, ContentType)
+		}
+		if got, want := recorder.Body.String(), `{"type":"FeatureCollection","features":[]}`; got != want {
+			t.Errorf("body = %s, want %s", got, want)
+		}
+	}
+
+	if fetches != 1 {
+		t.Errorf("layer fetched %d times, want 1", fetches)
+	}
+}
+
+func TestHandlerCacheDuration(t *testing.T) {
+	fetches := 0
+	handler := newHandler(func() (FeatureCollection, error) {
+		fetches++
+		return VesselsFeatureCollection(nil), nil
+	}, time.Minute, WithCacheDuration(0))
+
+	for i := 0; i < 2; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/vessels", nil))
+	}
+
+	if fetches != 2 {
+		t.Errorf("layer fetched %d times, want 2", fetches)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		fetchErr   error
+		wantStatus int
+		wantAllow  string
+	}{
+		{name: "head", method: http.MethodHead, wantStatus: http.StatusOK},
+		{name: "post", method: http.MethodPost, wantStatus: http.StatusMethodNotAllowed, wantAllow: "GET, HEAD"},
+		{name: "upstream error", method: http.MethodGet, fetchErr: errors.New("unavailable"), wantStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newHandler(func() (FeatureCollection, error) {
+				return VesselsFeatureCollection(nil), tt.fetchErr
+			}, time.Minute)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(tt.method, "/vessels", nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := recorder.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Package geo holds the spherical geometry shared by the API packages.
+package geo
+
+import (
+	"math"
+)
+
+const (
+	EarthRadiusMeters     = 6371008.8
+	MetersPerDegree       = EarthRadiusMeters * math.Pi / 180
+	MetersPerNauticalMile = 1852
+)
+
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// Distance returns the great-circle distance between a and b in meters.
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Bearing returns the initial bearing from a to b in degrees clockwise from
+// true north, in [0, 360).
+func Bearing(a, b Point) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLon := radians(b.Longitude - a.Longitude)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// AngleBetween returns the absolute difference between two bearings in
+// degrees, in [0, 180].
+func AngleBetween(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 360)
+	if d > 180 {
+		d = 360 - d
+	}
+
+	return d
+}
+
+// DistanceToSegment returns the distance in meters from p to the segment ab
+// and how far along the segment the closest point lies, as a fraction in
+// [0, 1]. It projects onto a plane around p, which is accurate for the
+// segment lengths found in road and ferry geometry.
+func DistanceToSegment(p, a, b Point) (float64, float64) {
+	scale := math.Cos(radians(p.Latitude))
+	project := func(q Point) (float64, float64) {
+		return (q.Longitude - p.Longitude) * scale * MetersPerDegree, (q.Latitude - p.Latitude) * MetersPerDegree
+	}
+
+	ax, ay := project(a)
+	bx, by := project(b)
+	dx, dy := bx-ax, by-ay
+
+	t := 0.0
+	if lengthSquared := dx*dx + dy*dy; lengthSquared > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/lengthSquared))
+	}
+
+	return math.Hypot(ax+t*dx, ay+t*dy), t
+}
@@ -0,0 +1,142 @@
+// Package httpcache serves generated documents over HTTP, rebuilding them at
+// most once per cache duration. It backs the live layer and feed handlers.
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds how long a build may take before requests stop
+	// waiting for it.
+	DefaultTimeout = 30 * time.Second
+)
+
+var (
+	ErrTimeout = errors.New("timed out building response")
+)
+
+// flight is a build in progress. done is closed once body and err are set.
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// Handler serves the body returned by its build function. Concurrent requests
+// share the cached body until it is older than the cache duration, and share
+// a single build when it has expired. Builds run outside the handler's lock,
+// so a slow upstream only delays the requests waiting on it.
+type Handler struct {
+	name          string
+	contentType   string
+	cacheDuration time.Duration
+	timeout       time.Duration
+	build         func(ctx context.Context) ([]byte, error)
+
+	mu        sync.Mutex
+	body      []byte
+	fetchedAt time.Time
+	flight    *flight
+}
+
+// New returns a handler for build. The name identifies the document in logs.
+// build is given a context that is cancelled after DefaultTimeout.
+func New(name, contentType string, cacheDuration time.Duration, build func(ctx context.Context) ([]byte, error)) *Handler {
+	return &Handler{
+		name:          name,
+		contentType:   contentType,
+		cacheDuration: cacheDuration,
+		timeout:       DefaultTimeout,
+		build:         build,
+	}
+}
+
+// start runs a build in the background. h.mu must be held.
+func (h *Handler) start() *flight {
+	f := &flight{done: make(chan struct{})}
+	h.flight = f
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+		defer cancel()
+
+		f.body, f.err = h.build(ctx)
+
+		h.mu.Lock()
+		if f.err == nil {
+			h.body, h.fetchedAt = f.body, time.Now()
+		}
+		h.flight = nil
+		h.mu.Unlock()
+
+		close(f.done)
+	}()
+
+	return f
+}
+
+// Body returns the cached body, rebuilding it once it has expired. While
+// another request is rebuilding an expired body, the expired body is
+// returned rather than waiting. A request waits for its own rebuild until ctx
+// is done or the timeout elapses, and then falls back to the expired body
+// when there is one.
+func (h *Handler) Body(ctx context.Context) ([]byte, error) {
+	h.mu.Lock()
+	if h.body != nil && time.Since(h.fetchedAt) < h.cacheDuration {
+		body := h.body
+		h.mu.Unlock()
+		return body, nil
+	}
+
+	stale := h.body
+	f, started := h.flight, false
+	if f == nil {
+		f, started = h.start(), true
+	}
+	h.mu.Unlock()
+
+	if stale != nil && !started {
+		return stale, nil
+	}
+
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-f.done:
+		return f.body, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		if stale != nil {
+			slog.Warn("timed out rebuilding "+h.name+", serving the previous one", "timeout", h.timeout)
+			return stale, nil
+		}
+		return nil, ErrTimeout
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := h.Body(r.Context())
+	if err != nil {
+		slog.Warn("error fetching "+h.name, "error", err)
+		http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", h.contentType)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,94 @@
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHandlerSlowBuild(t *testing.T) {
+	var builds atomic.Int32
+	release := make(chan struct{})
+
+	handler := New("test document", "text/plain", 0, func(ctx context.Context) ([]byte, error) {
+		// the first build succeeds, later ones hang until released
+		if builds.Add(1) == 1 {
+			return []byte("first"), nil
+		}
+
+		select {
+		case <-release:
+			return []byte("second"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	handler.timeout = 200 * time.Millisecond
+
+	if body, err := handler.Body(context.Background()); err != nil || string(body) != "first" {
+		t.Fatalf("Body() = %q, %v, want first", body, err)
+	}
+
+	// the request that starts the rebuild falls back to the expired body once
+	// the timeout elapses, and requests arriving meanwhile do not wait at all
+	type result struct {
+		body    string
+		elapsed time.Duration
+	}
+	results := make(chan result, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			start := time.Now()
+			body, _ := handler.Body(context.Background())
+			results <- result{string(body), time.Since(start)}
+		}()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var fast int
+	for i := 0; i < 2; i++ {
+		r := <-results
+		if r.body != "first" {
+			t.Errorf("Body() = %q during a slow rebuild, want first", r.body)
+		}
+		if r.elapsed < 150*time.Millisecond {
+			fast++
+		}
+	}
+	if fast != 1 {
+		t.Errorf("%d requests returned without waiting, want 1", fast)
+	}
+
+	close(release)
+	if got := builds.Load(); got != 2 {
+		t.Errorf("built %d times, want 2", got)
+	}
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	handler := New("test document", "text/plain", time.Minute, func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	handler.timeout = 10 * time.Millisecond
+
+	if _, err := handler.Body(context.Background()); !errors.Is(err, ErrTimeout) && !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Body() error = %v, want a timeout", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := handler.Body(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Body() error = %v, want %v", err, context.Canceled)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusBadGateway {
+		t.Errorf("ServeHTTP() status = %d, want %d", recorder.Code, http.StatusBadGateway)
+	}
+}
@@ -0,0 +1,138 @@
+package kml
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/cameras"
+	"alpineworks.io/wsdot/ferries"
+	"alpineworks.io/wsdot/internal/httpcache"
+)
+
+const (
+	DefaultCamerasCacheDuration   = 5 * time.Minute
+	DefaultVesselsCacheDuration   = 5 * time.Second
+	DefaultTerminalsCacheDuration = time.Hour
+)
+
+// Handler serves a live KML or KMZ layer, typically as the target of a
+// NetworkLink. The upstream API is queried at most once per cache duration.
+type Handler struct {
+	cacheDuration time.Duration
+	kmz           bool
+	cache         *httpcache.Handler
+}
+
+type HandlerOption func(*Handler)
+
+func WithCacheDuration(cacheDuration time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.cacheDuration = cacheDuration
+	}
+}
+
+// WithKMZ serves the layer as a KMZ archive instead of plain KML.
+func WithKMZ() HandlerOption {
+	return func(h *Handler) {
+		h.kmz = true
+	}
+}
+
+func NewCamerasHandler(camerasClient *cameras.CamerasClient, options ...HandlerOption) (*Handler, error) {
+	if camerasClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return newHandler(func() (*Document, error) {
+		cams, err := camerasClient.GetCameras()
+		if err != nil {
+			return nil, err
+		}
+
+		return CamerasDocument(cams), nil
+	}, DefaultCamerasCacheDuration, options...), nil
+}
+
+func NewVesselsHandler(ferriesClient *ferries.FerriesClient, options ...HandlerOption) (*Handler, error) {
+	if ferriesClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return newHandler(func() (*Document, error) {
+		vessels, err := ferriesClient.GetVesselLocations()
+		if err != nil {
+			return nil, err
+		}
+
+		return VesselsDocument(vessels), nil
+	}, DefaultVesselsCacheDuration, options...), nil
+}
+
+func NewTerminalsHandler(ferriesClient *ferries.FerriesClient, options ...HandlerOption) (*Handler, error) {
+	if ferriesClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return newHandler(func() (*Document, error) {
+		terminals, err := ferriesClient.GetTerminalLocations()
+		if err != nil {
+			return nil, err
+		}
+
+		return TerminalsDocument(terminals), nil
+	}, DefaultTerminalsCacheDuration, options...), nil
+}
+
+// NewNetworkLinkHandler serves a fixed document of NetworkLinks. Opening it in
+// Google Earth subscribes to the linked layers.
+func NewNetworkLinkHandler(name string, links []NetworkLink, options ...HandlerOption) *Handler {
+	document := NetworkLinkDocument(name, links...)
+
+	return newHandler(func() (*Document, error) {
+		return document, nil
+	}, time.Duration(math.MaxInt64), options...)
+}
+
+func newHandler(fetch func() (*Document, error), cacheDuration time.Duration, options ...HandlerOption) *Handler {
+	handler := &Handler{
+		cacheDuration: cacheDuration,
+	}
+
+	for _, option := range options {
+		option(handler)
+	}
+
+	contentType := ContentTypeKML
+	if handler.kmz {
+		contentType = ContentTypeKMZ
+	}
+
+	handler.cache = httpcache.New("kml layer", contentType, handler.cacheDuration, func(context.Context) ([]byte, error) {
+		document, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if handler.kmz {
+			err = document.WriteKMZ(&buf)
+		} else {
+			err = document.WriteKML(&buf)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	})
+
+	return handler
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.cache.ServeHTTP(w, r)
+}
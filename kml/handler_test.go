@@ -0,0 +1,99 @@
+package kml
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		options         []HandlerOption
+		wantContentType string
+		wantPrefix      string
+	}{
+		{name: "kml", wantContentType: ContentTypeKML, wantPrefix: "<?xml"},
+		{name: "kmz", options: []HandlerOption{WithKMZ()}, wantContentType: ContentTypeKMZ, wantPrefix: "PK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetches := 0
+			handler := newHandler(func() (*Document, error) {
+				fetches++
+				return NetworkLinkDocument("WSDOT"), nil
+			}, time.Minute, tt.options...)
+
+			for i := 0; i < 2; i++ {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/layer", nil))
+
+				if recorder.Code != http.StatusOK {
+					t.Fatalf("ServeHTTP() status = %d, want %d", recorder.Code, http.StatusOK)
+				}
+				if got := recorder.Header().Get("Content-Type"); got != tt.wantContentType {
+					t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+				}
+				if !bytes.HasPrefix(recorder.Body.Bytes(), []byte(tt.wantPrefix)) {
+					t.Errorf("body starts %q, want %q", recorder.Body.Bytes()[:min(8, recorder.Body.Len())], tt.wantPrefix)
+				}
+			}
+
+			if fetches != 1 {
+				t.Errorf("layer fetched %d times, want 1", fetches)
+			}
+		})
+	}
+}
+
+func TestHandlerCacheDuration(t *testing.T) {
+	fetches := 0
+	handler := newHandler(func() (*Document, error) {
+		fetches++
+		return NetworkLinkDocument("WSDOT"), nil
+	}, time.Minute, WithCacheDuration(0))
+
+	for i := 0; i < 2; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/layer", nil))
+	}
+
+	if fetches != 2 {
+		t.Errorf("layer fetched %d times, want 2", fetches)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		fetchErr   error
+		wantStatus int
+		wantAllow  string
+	}{
+		{name: "head", method: http.MethodHead, wantStatus: http.StatusOK},
+		{name: "put", method: http.MethodPut, wantStatus: http.StatusMethodNotAllowed, wantAllow: "GET, HEAD"},
+		{name: "upstream error", method: http.MethodGet, fetchErr: errors.New("unavailable"), wantStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newHandler(func() (*Document, error) {
+				return NetworkLinkDocument("WSDOT"), tt.fetchErr
+			}, time.Minute)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(tt.method, "/layer", nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := recorder.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+		})
+	}
+}
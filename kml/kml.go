@@ -0,0 +1,283 @@
+// Package kml writes cameras, vessel positions and ferry terminals as KML and
+// KMZ documents for Google Earth.
+package kml
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"alpineworks.io/wsdot/cameras"
+	"alpineworks.io/wsdot/ferries"
+)
+
+const (
+	ContentTypeKML = "application/vnd.google-earth.kml+xml"
+	ContentTypeKMZ = "application/vnd.google-earth.kmz"
+
+	namespace = "http://www.opengis.net/kml/2.2"
+
+	cameraIconURL   = "http://maps.google.com/mapfiles/kml/shapes/camera.png"
+	vesselIconURL   = "http://maps.google.com/mapfiles/kml/shapes/track.png"
+	terminalIconURL = "http://maps.google.com/mapfiles/kml/shapes/ferry.png"
+
+	cameraStyleID   = "camera"
+	vesselStyleID   = "vessel"
+	terminalStyleID = "terminal"
+
+	// thumbnails in camera balloons are scaled down to this width
+	thumbnailWidth = 320
+)
+
+type kml struct {
+	XMLName  xml.Name  `xml:"kml"`
+	Xmlns    string    `xml:"xmlns,attr"`
+	Document *Document `xml:"Document"`
+}
+
+type Document struct {
+	Name         string        `xml:"name"`
+	Styles       []style       `xml:"Style"`
+	Placemarks   []placemark   `xml:"Placemark"`
+	NetworkLinks []networkLink `xml:"NetworkLink"`
+}
+
+type style struct {
+	ID        string     `xml:"id,attr,omitempty"`
+	IconStyle *iconStyle `xml:"IconStyle,omitempty"`
+}
+
+type iconStyle struct {
+	Scale   float64 `xml:"scale,omitempty"`
+	Heading *int    `xml:"heading,omitempty"`
+	Icon    *icon   `xml:"Icon,omitempty"`
+}
+
+type icon struct {
+	Href string `xml:"href"`
+}
+
+type cdata struct {
+	Text string `xml:",cdata"`
+}
+
+type placemark struct {
+	ID          string `xml:"id,attr,omitempty"`
+	Name        string `xml:"name"`
+	Description *cdata `xml:"description,omitempty"`
+	StyleURL    string `xml:"styleUrl,omitempty"`
+	Style       *style `xml:"Style,omitempty"`
+	Point       point  `xml:"Point"`
+}
+
+type point struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type networkLink struct {
+	Name              string `xml:"name"`
+	RefreshVisibility int    `xml:"refreshVisibility"`
+	Link              link   `xml:"Link"`
+}
+
+type link struct {
+	Href            string  `xml:"href"`
+	RefreshMode     string  `xml:"refreshMode,omitempty"`
+	RefreshInterval float64 `xml:"refreshInterval,omitempty"`
+}
+
+func coordinates(latitude, longitude float64) point {
+	return point{
+		Coordinates: strconv.FormatFloat(longitude, 'f', -1, 64) + "," + strconv.FormatFloat(latitude, 'f', -1, 64) + ",0",
+	}
+}
+
+func iconStyleFor(id, href string) style {
+	return style{
+		ID: id,
+		IconStyle: &iconStyle{
+			Scale: 1,
+			Icon:  &icon{Href: href},
+		},
+	}
+}
+
+func CamerasDocument(cams []cameras.Camera) *Document {
+	document := &Document{
+		Name:   "WSDOT Cameras",
+		Styles: []style{iconStyleFor(cameraStyleID, cameraIconURL)},
+	}
+
+	for _, camera := range cams {
+		latitude, longitude := camera.DisplayLatitude, camera.DisplayLongitude
+		if latitude == 0 && longitude == 0 {
+			latitude, longitude = camera.CameraLocation.Latitude, camera.CameraLocation.Longitude
+		}
+
+		width, height := camera.ImageWidth, camera.ImageHeight
+		if width > thumbnailWidth {
+			height = height * thumbnailWidth / width
+			width = thumbnailWidth
+		}
+
+		var balloon strings.Builder
+		fmt.Fprintf(&balloon, `<img src="%s"`, html.EscapeString(camera.ImageURL))
+		if width > 0 && height > 0 {
+			fmt.Fprintf(&balloon, ` width="%d" height="%d"`, width, height)
+		}
+		balloon.WriteString(`/>`)
+		if camera.Description.Valid {
+			fmt.Fprintf(&balloon, `<br/>%s`, html.EscapeString(camera.Description.Value))
+		}
+		fmt.Fprintf(&balloon, `<br/>%s milepost %d %s`, html.EscapeString(camera.CameraLocation.RoadName), camera.CameraLocation.MilePost, html.EscapeString(camera.CameraLocation.Direction))
+		if camera.CameraOwner.Valid {
+			fmt.Fprintf(&balloon, `<br/>Owner: %s`, html.EscapeString(camera.CameraOwner.Value))
+		}
+
+		document.Placemarks = append(document.Placemarks, placemark{
+			ID:          "camera-" + strconv.Itoa(camera.CameraID),
+			Name:        camera.Title,
+			Description: &cdata{Text: balloon.String()},
+			StyleURL:    "#" + cameraStyleID,
+			Point:       coordinates(latitude, longitude),
+		})
+	}
+
+	return document
+}
+
+func VesselsDocument(vessels []ferries.VesselLocation) *Document {
+	document := &Document{
+		Name:   "WSF Vessels",
+		Styles: []style{iconStyleFor(vesselStyleID, vesselIconURL)},
+	}
+
+	for _, vessel := range vessels {
+		heading := vessel.Heading
+
+		var balloon strings.Builder
+		if vessel.DepartingTerminalName != "" {
+			fmt.Fprintf(&balloon, `%s`, html.EscapeString(vessel.DepartingTerminalName))
+			if vessel.ArrivingTerminalName != "" {
+				fmt.Fprintf(&balloon, ` to %s`, html.EscapeString(vessel.ArrivingTerminalName))
+			}
+			balloon.WriteString(`<br/>`)
+		}
+		fmt.Fprintf(&balloon, `%.1f knots, heading %d°`, vessel.Speed, vessel.Heading)
+		if !vessel.InService {
+			balloon.WriteString(`<br/>Out of service`)
+		}
+
+		document.Placemarks = append(document.Placemarks, placemark{
+			ID:          "vessel-" + strconv.Itoa(vessel.VesselID),
+			Name:        vessel.VesselName,
+			Description: &cdata{Text: balloon.String()},
+			StyleURL:    "#" + vesselStyleID,
+			Style: &style{
+				IconStyle: &iconStyle{
+					Scale:   1,
+					Heading: &heading,
+					Icon:    &icon{Href: vesselIconURL},
+				},
+			},
+			Point: coordinates(vessel.Latitude, vessel.Longitude),
+		})
+	}
+
+	return document
+}
+
+func TerminalsDocument(terminals []ferries.TerminalLocation) *Document {
+	document := &Document{
+		Name:   "WSF Terminals",
+		Styles: []style{iconStyleFor(terminalStyleID, terminalIconURL)},
+	}
+
+	for _, terminal := range terminals {
+		var balloon strings.Builder
+		fmt.Fprintf(&balloon, `%s<br/>%s, %s %s`, html.EscapeString(terminal.AddressLineOne), html.EscapeString(terminal.City), html.EscapeString(terminal.State), html.EscapeString(terminal.ZipCode))
+		if terminal.MapLink != "" {
+			fmt.Fprintf(&balloon, `<br/><a href="%s">Map</a>`, html.EscapeString(terminal.MapLink))
+		}
+
+		document.Placemarks = append(document.Placemarks, placemark{
+			ID:          "terminal-" + strconv.Itoa(terminal.TerminalID),
+			Name:        terminal.TerminalName,
+			Description: &cdata{Text: balloon.String()},
+			StyleURL:    "#" + terminalStyleID,
+			Point:       coordinates(terminal.Latitude, terminal.Longitude),
+		})
+	}
+
+	return document
+}
+
+type NetworkLink struct {
+	Name string
+	Href string
+	// RefreshInterval is how often Google Earth reloads the link. Zero loads
+	// it once.
+	RefreshInterval time.Duration
+}
+
+// NetworkLinkDocument returns a document that loads each link from a server,
+// typically the handlers in this package, and refreshes them on an interval.
+func NetworkLinkDocument(name string, links ...NetworkLink) *Document {
+	document := &Document{
+		Name: name,
+	}
+
+	for _, l := range links {
+		nl := networkLink{
+			Name: l.Name,
+			Link: link{Href: l.Href},
+		}
+		if l.RefreshInterval > 0 {
+			nl.Link.RefreshMode = "onInterval"
+			nl.Link.RefreshInterval = l.RefreshInterval.Seconds()
+		}
+
+		document.NetworkLinks = append(document.NetworkLinks, nl)
+	}
+
+	return document
+}
+
+func (d *Document) WriteKML(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("error writing kml: %v", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(kml{Xmlns: namespace, Document: d}); err != nil {
+		return fmt.Errorf("error encoding kml: %v", err)
+	}
+
+	return nil
+}
+
+// WriteKMZ writes the document as a zipped KMZ archive containing doc.kml.
+func (d *Document) WriteKMZ(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	doc, err := archive.Create("doc.kml")
+	if err != nil {
+		return fmt.Errorf("error creating kmz: %v", err)
+	}
+
+	if err := d.WriteKML(doc); err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("error writing kmz: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package kml
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"alpineworks.io/wsdot/cameras"
+	"alpineworks.io/wsdot/ferries"
+)
+
+// decoded mirrors the parts of a KML document the tests check.
+type decoded struct {
+	XMLName  xml.Name `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Document struct {
+		Name       string `xml:"name"`
+		Placemarks []struct {
+			ID          string `xml:"id,attr"`
+			Name        string `xml:"name"`
+			Description string `xml:"description"`
+			StyleURL    string `xml:"styleUrl"`
+			Point       struct {
+				Coordinates string `xml:"coordinates"`
+			} `xml:"Point"`
+		} `xml:"Placemark"`
+		NetworkLinks []struct {
+			Name string `xml:"name"`
+			Link struct {
+				Href            string  `xml:"href"`
+				RefreshMode     string  `xml:"refreshMode"`
+				RefreshInterval float64 `xml:"refreshInterval"`
+			} `xml:"Link"`
+		} `xml:"NetworkLink"`
+	} `xml:"Document"`
+}
+
+func decodeKML(t *testing.T, data []byte) decoded {
+	t.Helper()
+
+	var d decoded
+	if err := xml.Unmarshal(data, &d); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v", err)
+	}
+
+	return d
+}
+
+func TestCamerasDocument(t *testing.T) {
+	cams := []cameras.Camera{
+		{
+			CameraID:       9818,
+			Title:          "I-5 at NE 45th St",
+			Description:    cameras.NewNullableString("Looking <north>"),
+			CameraLocation: cameras.CameraLocation{Latitude: 47.6612, Longitude: -122.323, RoadName: "I-5", MilePost: 169, Direction: "B"},
+			ImageURL:       "https://images.wsdot.wa.gov/nw/005vc16930.jpg",
+			ImageWidth:     640,
+			ImageHeight:    480,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := CamerasDocument(cams).WriteKML(&buf); err != nil {
+		t.Fatalf("WriteKML() error = %v", err)
+	}
+
+	d := decodeKML(t, buf.Bytes())
+	if d.Document.Name != "WSDOT Cameras" || len(d.Document.Placemarks) != 1 {
+		t.Fatalf("CamerasDocument() = %+v, want one camera placemark", d.Document)
+	}
+
+	placemark := d.Document.Placemarks[0]
+	if placemark.ID != "camera-9818" || placemark.StyleURL != "#camera" {
+		t.Errorf("placemark id = %q, styleUrl = %q, want camera-9818, #camera", placemark.ID, placemark.StyleURL)
+	}
+	// KML coordinates are longitude first; display coordinates fall back to
+	// the camera location when unset
+	if want := "-122.323,47.6612,0"; placemark.Point.Coordinates != want {
+		t.Errorf("coordinates = %q, want %q", placemark.Point.Coordinates, want)
+	}
+	// thumbnails are scaled to 320 pixels wide and text is escaped
+	for _, want := range []string{`width="320" height="240"`, "Looking &lt;north&gt;", "I-5 milepost 169 B"} {
+		if !strings.Contains(placemark.Description, want) {
+			t.Errorf("description = %q, want it to contain %q", placemark.Description, want)
+		}
+	}
+}
+
+func TestVesselsDocument(t *testing.T) {
+	vessels := []ferries.VesselLocation{
+		{VesselID: 2, VesselName: "Chimacum", Latitude: 47.6025, Longitude: -122.3388, Speed: 17.4, Heading: 280, DepartingTerminalName: "Seattle", ArrivingTerminalName: "Bainbridge Island", InService: true},
+		{VesselID: 36, VesselName: "Walla Walla", Latitude: 47.5879, Longitude: -122.6222},
+	}
+
+	var buf bytes.Buffer
+	if err := VesselsDocument(vessels).WriteKML(&buf); err != nil {
+		t.Fatalf("WriteKML() error = %v", err)
+	}
+
+	d := decodeKML(t, buf.Bytes())
+	if len(d.Document.Placemarks) != 2 {
+		t.Fatalf("VesselsDocument() has %d placemarks, want 2", len(d.Document.Placemarks))
+	}
+
+	tests := []struct {
+		name        string
+		description string
+	}{
+		{name: "Chimacum", description: "Seattle to Bainbridge Island<br/>17.4 knots, heading 280°"},
+		{name: "Walla Walla", description: "0.0 knots, heading 0°<br/>Out of service"},
+	}
+
+	for i, tt := range tests {
+		placemark := d.Document.Placemarks[i]
+		if placemark.Name != tt.name || placemark.Description != tt.description {
+			t.Errorf("placemark %d = %q %q, want %q %q", i, placemark.Name, placemark.Description, tt.name, tt.description)
+		}
+	}
+}
+
+func TestNetworkLinkDocumentKMZ(t *testing.T) {
+	document := NetworkLinkDocument("WSDOT",
+		NetworkLink{Name: "Vessels", Href: "https://example.com/vessels.kml", RefreshInterval: 10 * time.Second},
+		NetworkLink{Name: "Terminals", Href: "https://example.com/terminals.kml"},
+	)
+
+	var buf bytes.Buffer
+	if err := document.WriteKMZ(&buf); err != nil {
+		t.Fatalf("WriteKMZ() error = %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	if len(archive.File) != 1 || archive.File[0].Name != "doc.kml" {
+		t.Fatalf("kmz holds %d files, want doc.kml only", len(archive.File))
+	}
+
+	file, err := archive.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := decodeKML(t, data)
+	if len(d.Document.NetworkLinks) != 2 {
+		t.Fatalf("NetworkLinkDocument() has %d links, want 2", len(d.Document.NetworkLinks))
+	}
+
+	vessels, terminals := d.Document.NetworkLinks[0].Link, d.Document.NetworkLinks[1].Link
+	if vessels.RefreshMode != "onInterval" || vessels.RefreshInterval != 10 {
+		t.Errorf("vessels link = %+v, want a 10 second interval refresh", vessels)
+	}
+	if terminals.RefreshMode != "" || terminals.RefreshInterval != 0 {
+		t.Errorf("terminals link = %+v, want no refresh", terminals)
+	}
+}
@@ -0,0 +1,100 @@
+// Package wsdotapi exposes every WSDOT API family from a single configured
+// client. The family clients are created on first use and share the
+// underlying WSDOTClient, including its HTTP client and API key.
+package wsdotapi
+
+import (
+	"sync"
+
+	"alpineworks.io/wsdot"
+	"alpineworks.io/wsdot/bordercrossings"
+	"alpineworks.io/wsdot/bridgeclearances"
+	"alpineworks.io/wsdot/cameras"
+	"alpineworks.io/wsdot/cvrestrictions"
+	"alpineworks.io/wsdot/ferries"
+)
+
+// Client hands out the family clients, creating each once on first use. The
+// family constructors only fail on a nil WSDOTClient, which
+// NewClientFromWSDOTClient rules out, so their errors are dropped.
+type Client struct {
+	wsdot *wsdot.WSDOTClient
+
+	camerasOnce sync.Once
+	cameras     *cameras.CamerasClient
+
+	ferriesOnce sync.Once
+	ferries     *ferries.FerriesClient
+
+	cvRestrictionsOnce sync.Once
+	cvRestrictions     *cvrestrictions.CVRestrictionsClient
+
+	bridgeClearancesOnce sync.Once
+	bridgeClearances     *bridgeclearances.BridgeClearancesClient
+
+	borderCrossingsOnce sync.Once
+	borderCrossings     *bordercrossings.BorderCrossingsClient
+}
+
+func NewClient(options ...wsdot.WSDOTClientOption) (*Client, error) {
+	wsdotClient, err := wsdot.NewWSDOTClient(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientFromWSDOTClient(wsdotClient)
+}
+
+func NewClientFromWSDOTClient(wsdotClient *wsdot.WSDOTClient) (*Client, error) {
+	if wsdotClient == nil {
+		return nil, wsdot.ErrNoClient
+	}
+
+	return &Client{
+		wsdot: wsdotClient,
+	}, nil
+}
+
+func (c *Client) WSDOT() *wsdot.WSDOTClient {
+	return c.wsdot
+}
+
+func (c *Client) Cameras() *cameras.CamerasClient {
+	c.camerasOnce.Do(func() {
+		c.cameras, _ = cameras.NewCamerasClient(c.wsdot)
+	})
+
+	return c.cameras
+}
+
+func (c *Client) Ferries() *ferries.FerriesClient {
+	c.ferriesOnce.Do(func() {
+		c.ferries, _ = ferries.NewFerriesClient(c.wsdot)
+	})
+
+	return c.ferries
+}
+
+func (c *Client) CVRestrictions() *cvrestrictions.CVRestrictionsClient {
+	c.cvRestrictionsOnce.Do(func() {
+		c.cvRestrictions, _ = cvrestrictions.NewCVRestrictionsClient(c.wsdot)
+	})
+
+	return c.cvRestrictions
+}
+
+func (c *Client) BridgeClearances() *bridgeclearances.BridgeClearancesClient {
+	c.bridgeClearancesOnce.Do(func() {
+		c.bridgeClearances, _ = bridgeclearances.NewBridgeClearancesClient(c.wsdot)
+	})
+
+	return c.bridgeClearances
+}
+
+func (c *Client) BorderCrossings() *bordercrossings.BorderCrossingsClient {
+	c.borderCrossingsOnce.Do(func() {
+		c.borderCrossings, _ = bordercrossings.NewBorderCrossingsClient(c.wsdot)
+	})
+
+	return c.borderCrossings
+}
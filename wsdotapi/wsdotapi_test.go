@@ -0,0 +1,68 @@
+package wsdotapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"alpineworks.io/wsdot"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   []wsdot.WSDOTClientOption
+		expectErr error
+	}{
+		{name: "API key", options: []wsdot.WSDOTClientOption{wsdot.WithAPIKey("test")}},
+		{name: "No API key", expectErr: wsdot.ErrInvalidAPIKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.options...)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("NewClient() error = %v, want %v", err, tt.expectErr)
+			}
+			if tt.expectErr == nil && client.WSDOT() == nil {
+				t.Errorf("NewClient().WSDOT() = nil")
+			}
+		})
+	}
+
+	if _, err := NewClientFromWSDOTClient(nil); !errors.Is(err, wsdot.ErrNoClient) {
+		t.Errorf("NewClientFromWSDOTClient(nil) error = %v, want %v", err, wsdot.ErrNoClient)
+	}
+}
+
+func TestFamilyClients(t *testing.T) {
+	client, err := NewClient(wsdot.WithAPIKey("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		family func() any
+	}{
+		{name: "Cameras", family: func() any { return client.Cameras() }},
+		{name: "Ferries", family: func() any { return client.Ferries() }},
+		{name: "CVRestrictions", family: func() any { return client.CVRestrictions() }},
+		{name: "BridgeClearances", family: func() any { return client.BridgeClearances() }},
+		{name: "BorderCrossings", family: func() any { return client.BorderCrossings() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the accessors return typed pointers, so check for nil through
+			// reflection rather than comparing the interface
+			first := tt.family()
+			if reflect.ValueOf(first).IsNil() {
+				t.Fatalf("%s() = nil", tt.name)
+			}
+			if second := tt.family(); second != first {
+				t.Errorf("%s() returned a new client on the second call", tt.name)
+			}
+		})
+	}
+}